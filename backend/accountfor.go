@@ -0,0 +1,75 @@
+package backend
+
+import "time"
+
+// DefaultAccountTolerance is how far a day may be from the target of
+// ReportOptions.AccountFor and still pass
+const DefaultAccountTolerance = 15 * time.Minute
+
+// AccountTemplateString defines the template used to output a Report()
+// with FormatText when days are checked with ReportOptions.AccountFor
+var AccountTemplateString = `{{with .Accounting -}}
+Accounting for {{.Target}} per day (±{{.Tolerance}}): {{$.From.Format "2006-01-02"}} to {{($.To.AddDate 0 0 -1).Format "2006-01-02"}}
+{{range .Days}}
+{{.Date.Format "2006-01-02 Mon"}} {{pad 10 .Worked.String}}  {{if .Pass}}PASS{{else}}{{style "yellow"}}FAIL {{.Off}}{{style "reset"}}{{end}}
+{{- end}}
+
+{{if .Failed}}{{style "bold"}}FAIL{{style "reset"}}: {{.Failed}} of {{len .Days}} days not accounted for{{else}}PASS: all {{len .Days}} days accounted for{{end}}
+{{end -}}
+`
+
+// Accounting is the result of checking that each day of a report adds up
+// to a target number of hours
+type Accounting struct {
+	Target    time.Duration  `json:"target"`
+	Tolerance time.Duration  `json:"tolerance"`
+	Days      []AccountedDay `json:"days"`
+	Failed    int            `json:"failed"`
+}
+
+// AccountedDay compares the task and break hours of a day to the target
+// Diff is worked minus target, so it is negative for short days.
+type AccountedDay struct {
+	Date   time.Time     `json:"date"`
+	Worked time.Duration `json:"worked"`
+	Diff   time.Duration `json:"diff"`
+	Pass   bool          `json:"pass"`
+}
+
+// Off describes how far the day is from the target, such as "under by 1h0m0s"
+func (d AccountedDay) Off() string {
+	if d.Diff < 0 {
+		return "under by " + (-d.Diff).String()
+	}
+	return "over by " + d.Diff.String()
+}
+
+// accountFor checks the task plus break hours of each weekday of the
+// report, and of any weekend day with entries, against target
+// Days after today haven't happened yet and are left out.
+func accountFor(report Report, target, tolerance time.Duration) *Accounting {
+	tracked := make(map[string]DayTotal)
+	for _, day := range dayTotals(report.Entries) {
+		tracked[day.Date.Format("2006-01-02")] = day
+	}
+	acc := &Accounting{Target: target, Tolerance: tolerance, Days: []AccountedDay{}}
+	end := report.To
+	if now := time.Now(); now.Before(end) {
+		end = now
+	}
+	for date := report.From; date.Before(end); date = date.AddDate(0, 0, 1) {
+		total, ok := tracked[date.Format("2006-01-02")]
+		weekend := date.Weekday() == time.Saturday || date.Weekday() == time.Sunday
+		if !ok && weekend {
+			continue
+		}
+		day := AccountedDay{Date: date, Worked: total.TaskHrs + total.BrkHrs}
+		day.Diff = day.Worked - target
+		day.Pass = day.Diff <= tolerance && day.Diff >= -tolerance
+		if !day.Pass {
+			acc.Failed++
+		}
+		acc.Days = append(acc.Days, day)
+	}
+	return acc
+}
@@ -0,0 +1,46 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_accountFor(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2020, 3, day, hour, 0, 0, 0, time.UTC)
+	}
+	report := Report{
+		// Friday March 6 to Monday March 9
+		From: at(6, 0),
+		To:   at(10, 0),
+		Entries: []ReportEntry{
+			{Title: "api", Duration: 7 * time.Hour, Ts: at(6, 16)},
+			{Title: "lunch", Brk: true, Duration: 50 * time.Minute, Ts: at(6, 17)},
+			{Title: "deploy", Duration: time.Hour, Ts: at(7, 12)},
+			{Title: "api", Duration: 6 * time.Hour, Ts: at(9, 15)},
+		},
+	}
+	acc := accountFor(report, 8*time.Hour, 15*time.Minute)
+	want := []struct {
+		day  int
+		pass bool
+	}{
+		{6, true},
+		{7, false},
+		{9, false},
+	}
+	if len(acc.Days) != len(want) {
+		t.Fatalf("accountFor() checked %d days, want %d - Sunday has no entries", len(acc.Days), len(want))
+	}
+	for i, w := range want {
+		if d := acc.Days[i]; d.Date.Day() != w.day || d.Pass != w.pass {
+			t.Errorf("day %d = March %d pass %v, want March %d pass %v", i, d.Date.Day(), d.Pass, w.day, w.pass)
+		}
+	}
+	if acc.Failed != 2 {
+		t.Errorf("accountFor() failed = %d, want 2", acc.Failed)
+	}
+	if off := acc.Days[2].Off(); off != "under by 2h0m0s" {
+		t.Errorf("Off() = %q, want under by 2h0m0s", off)
+	}
+}
@@ -0,0 +1,42 @@
+package backend
+
+import (
+	"fmt"
+	"time"
+)
+
+// AccuracyTemplateString defines the template used to output a Report()
+// with FormatText when an accuracy check is requested
+var AccuracyTemplateString = `Accuracy Check: {{.From.Format "2006-01-02"}} to {{(.To.AddDate 0 0 -1).Format "2006-01-02"}}
+{{range .Suspects}}
+{{.Entry.Ts.Format "2006-01-02 15:04"}} ({{.Entry.Duration}}) {{.Entry.Title}} -- {{.Reason}}
+{{- else}}
+No suspicious entries found
+{{- end}}
+`
+
+// Suspect describes a report entry whose duration looks mis-logged
+type Suspect struct {
+	Entry  ReportEntry `json:"entry"`
+	Reason string      `json:"reason"`
+}
+
+// accuracyCheck lists the entries of a report that are shorter than min
+// or longer than max, which usually means a task switch was logged twice
+// or forgotten
+// Zero length entries start a new day or session and are not suspect.
+// A zero min or max disables that check.
+func accuracyCheck(entries []ReportEntry, min, max time.Duration) []Suspect {
+	suspects := []Suspect{}
+	for _, entry := range entries {
+		switch {
+		case entry.Duration == 0:
+			continue
+		case min > 0 && entry.Duration < min:
+			suspects = append(suspects, Suspect{entry, fmt.Sprintf("shorter than %s", min)})
+		case max > 0 && entry.Duration > max:
+			suspects = append(suspects, Suspect{entry, fmt.Sprintf("longer than %s", max)})
+		}
+	}
+	return suspects
+}
@@ -0,0 +1,55 @@
+package backend
+
+import "time"
+
+// BreakCategoryPrefix marks a break with a category, as in "team sync **:meeting"
+const BreakCategoryPrefix = "**:"
+
+// UncategorizedBreak is the category of breaks marked with a plain **
+const UncategorizedBreak = "uncategorized"
+
+// addBreak adds the duration of a break entry to its category
+func (r *Report) addBreak(entry *ReportEntry) {
+	if r.BrkCategories == nil {
+		r.BrkCategories = make(map[string]time.Duration)
+	}
+	category := entry.BrkCategory
+	if category == "" {
+		category = UncategorizedBreak
+	}
+	r.BrkCategories[category] += entry.Duration
+}
+
+// payBreak counts a short break toward the task hours under a paid break
+// policy, leaving it in the break hours as well so both stay auditable
+// The entry is marked Paid so day totals and invoices count it as work.
+func (r *Report) payBreak(entry *ReportEntry, billable bool) {
+	entry.Paid = true
+	r.TaskHrs += entry.Duration
+	r.PaidBrkHrs += entry.Duration
+	if billable {
+		r.BillableHrs += entry.Duration
+	}
+}
+
+// offHours reports whether a break falls on a weekend or entirely outside
+// the working window from dayStart to dayEnd
+func offHours(entry *ReportEntry, dayStart, dayEnd time.Duration) bool {
+	if day := entry.Start.Weekday(); day == time.Saturday || day == time.Sunday {
+		return true
+	}
+	y, m, d := entry.Start.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, entry.Start.Location())
+	end := entry.Start.Add(entry.Duration)
+	return !end.After(midnight.Add(dayStart)) || !entry.Start.Before(midnight.Add(dayEnd))
+}
+
+// categorizedBreaks returns the break categories of a report, or nil if
+// no break had a category, so that reports without categories stay as
+// they were
+func categorizedBreaks(categories map[string]time.Duration) map[string]time.Duration {
+	if _, ok := categories[UncategorizedBreak]; ok && len(categories) == 1 {
+		return nil
+	}
+	return categories
+}
@@ -0,0 +1,35 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_offHours(t *testing.T) {
+	// Monday March 2 and Saturday March 7
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2020, 3, day, hour, min, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name     string
+		start    time.Time
+		duration time.Duration
+		want     bool
+	}{
+		{"during the day", at(2, 12, 0), 30 * time.Minute, false},
+		{"before day start", at(2, 7, 30), time.Hour, true},
+		{"ending at day start", at(2, 8, 30), 30 * time.Minute, true},
+		{"crossing day start", at(2, 8, 45), 30 * time.Minute, false},
+		{"crossing day end", at(2, 16, 45), 30 * time.Minute, false},
+		{"starting at day end", at(2, 17, 0), 15 * time.Minute, true},
+		{"Saturday", at(7, 12, 0), 30 * time.Minute, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &ReportEntry{Brk: true, Start: tt.start, Duration: tt.duration}
+			if got := offHours(entry, 9*time.Hour, 17*time.Hour); got != tt.want {
+				t.Errorf("offHours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package backend
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// entryCache holds the most recently read timesheet so that long running
+// commands such as omw watch don't parse the whole file on every redraw
+// The cache is only used while the file's size and modification time are
+// unchanged, so edits made outside of omw are always picked up.  The zero
+// value is an empty cache.
+type entryCache struct {
+	mu      sync.Mutex
+	data    *SavedItems
+	modTime time.Time
+	size    int64
+}
+
+// get returns a copy of the cached entries if they were read from a file
+// that matches info, or nil
+// Callers sort and modify the entries they get, so they never share the
+// cached slice.
+func (c *entryCache) get(info os.FileInfo) *SavedItems {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.data == nil || !info.ModTime().Equal(c.modTime) || info.Size() != c.size {
+		return nil
+	}
+	return copyItems(c.data)
+}
+
+// put caches a copy of data as the contents of the file described by info
+func (c *entryCache) put(info os.FileInfo, data *SavedItems) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data = copyItems(data)
+	c.modTime = info.ModTime()
+	c.size = info.Size()
+}
+
+// invalidate empties the cache after a write it can't follow
+func (c *entryCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data = nil
+}
+
+func copyItems(data *SavedItems) *SavedItems {
+	entries := make([]SavedEntry, len(data.Entries))
+	copy(entries, data.Entries)
+	return &SavedItems{Entries: entries}
+}
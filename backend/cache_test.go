@@ -0,0 +1,89 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// benchmarkData returns a timesheet of n entries, about a year of tasks
+// for n = 5000
+func benchmarkData(n int) string {
+	var sb strings.Builder
+	start := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, "[[entries]]\n  id = \"%d\"\n  end = %s\n  task = \"task %d @acme #dev\"\n",
+			i, start.Add(time.Duration(i)*time.Hour).Format(time.RFC3339), i)
+	}
+	return sb.String()
+}
+
+func TestBackend_readEntries_cache(t *testing.T) {
+	b, cleanup := newTestBackend(t, benchmarkData(3))
+	defer cleanup()
+	first, err := b.readEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// callers may modify what they read without touching the cache
+	first.Entries[0].Task = "changed"
+	second, err := b.readEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Entries[0].Task != "task 0 @acme #dev" {
+		t.Errorf("cached entry = %q, want the task on disk", second.Entries[0].Task)
+	}
+	if _, err := b.addEntry("new task"); err != nil {
+		t.Fatal(err)
+	}
+	third, err := b.readEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(third.Entries) != 4 {
+		t.Errorf("got %d entries after add, want 4", len(third.Entries))
+	}
+}
+
+func TestBackend_writeEntries_cache(t *testing.T) {
+	b, cleanup := newTestBackend(t, benchmarkData(1))
+	defer cleanup()
+	end := time.Date(2020, 1, 1, 10, 30, 0, 123456789, time.UTC)
+	err := b.updateEntries(func(data *SavedItems) (bool, error) {
+		data.Entries = append(data.Entries, SavedEntry{ID: "1", End: end, Task: "review"})
+		return true, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := b.readEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.cache.invalidate()
+	onDisk, err := b.readEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cached.Entries[1].End, onDisk.Entries[1].End; !got.Equal(want) {
+		t.Errorf("cached end = %s, want %s as read from disk", got, want)
+	}
+}
+
+func BenchmarkBackend_Report(b *testing.B) {
+	omw, cleanup := newTestBackend(b, benchmarkData(5000))
+	defer cleanup()
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			omw.Report("2020-01-01", "2020-12-31", "json", ReportOptions{})
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			omw.cache.invalidate()
+			omw.Report("2020-01-01", "2020-12-31", "json", ReportOptions{})
+		}
+	})
+}
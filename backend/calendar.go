@@ -0,0 +1,86 @@
+package backend
+
+import (
+	"fmt"
+	"time"
+)
+
+// CalendarTemplateString defines the template used to output a Report()
+// with FormatText when a calendar is requested
+// Days over the expected hours are green and days under them yellow.
+var CalendarTemplateString = `Task Hours by Day: {{.From.Format "2006-01-02"}} to {{(.To.AddDate 0 0 -1).Format "2006-01-02"}}
+{{with .Calendar}}
+{{range .Weekdays}}{{pad 10 .}}{{end}}
+{{range .Weeks}}
+{{- range .}}{{if .Over}}{{style "green"}}{{else if .Under}}{{style "yellow"}}{{end}}{{pad 10 .Cell}}{{if or .Over .Under}}{{style "reset"}}{{end}}{{end}}
+{{end}}
+{{- end}}`
+
+// Calendar lays out the task hours of each day of a report as weeks
+type Calendar struct {
+	Weekdays []string        `json:"weekdays"`
+	Weeks    [][]CalendarDay `json:"weeks"`
+}
+
+// CalendarDay is one cell of a Calendar
+// Days of the first and last week that fall outside the report are
+// included to fill the week, with InRange false.
+type CalendarDay struct {
+	Date     time.Time     `json:"date"`
+	TaskHrs  time.Duration `json:"taskTotalHours"`
+	Expected time.Duration `json:"expectedHours"`
+	InRange  bool          `json:"inRange"`
+}
+
+// Over reports whether more than the expected hours were worked
+func (d CalendarDay) Over() bool {
+	return d.InRange && d.Expected > 0 && d.TaskHrs > d.Expected
+}
+
+// Under reports whether fewer than the expected hours were worked on a
+// day that has already started
+func (d CalendarDay) Under() bool {
+	return d.InRange && d.TaskHrs < d.Expected && d.Date.Before(time.Now())
+}
+
+// Cell returns the day of the month and its task hours, such as "12 7.5h"
+func (d CalendarDay) Cell() string {
+	if !d.InRange {
+		return ""
+	}
+	if d.TaskHrs == 0 {
+		return fmt.Sprintf("%d -", d.Date.Day())
+	}
+	return fmt.Sprintf("%d %.1fh", d.Date.Day(), d.TaskHrs.Hours())
+}
+
+// calendar arranges the task hours of a report into weeks beginning on
+// weekStart, expecting the given hours on weekdays like runningBalance()
+func calendar(report Report, expected time.Duration, weekStart time.Weekday, locale string) *Calendar {
+	tracked := make(map[string]time.Duration)
+	for _, day := range dayTotals(report.Entries) {
+		tracked[day.Date.Format("2006-01-02")] = day.TaskHrs
+	}
+	cal := &Calendar{}
+	first, _ := WeekRange(report.From, weekStart)
+	for i := 0; i < 7; i++ {
+		name := []rune(weekdayName(locale, first.AddDate(0, 0, i)))
+		cal.Weekdays = append(cal.Weekdays, string(name[:3]))
+	}
+	for week := first; week.Before(report.To); week = week.AddDate(0, 0, 7) {
+		days := []CalendarDay{}
+		for i := 0; i < 7; i++ {
+			date := week.AddDate(0, 0, i)
+			day := CalendarDay{Date: date, InRange: !date.Before(report.From) && date.Before(report.To)}
+			if day.InRange {
+				day.TaskHrs = tracked[date.Format("2006-01-02")]
+				if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+					day.Expected = expected
+				}
+			}
+			days = append(days, day)
+		}
+		cal.Weeks = append(cal.Weeks, days)
+	}
+	return cal
+}
@@ -0,0 +1,95 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_calendar(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2020, 3, d, 0, 0, 0, 0, time.UTC)
+	}
+	// March 2020 starts on a Sunday
+	report := Report{
+		From: day(1),
+		To:   day(32),
+		Entries: []ReportEntry{
+			{Title: "api", Duration: 9 * time.Hour, Ts: day(2).Add(17 * time.Hour)},
+			{Title: "api", Duration: 4 * time.Hour, Ts: day(3).Add(13 * time.Hour)},
+			{Title: "lunch", Brk: true, Duration: 4 * time.Hour, Ts: day(3).Add(17 * time.Hour)},
+			{Title: "api", Duration: 8 * time.Hour, Ts: day(4).Add(17 * time.Hour)},
+			{Title: "docs", Duration: 2 * time.Hour, Ts: day(7).Add(12 * time.Hour)},
+		},
+	}
+	tests := []struct {
+		name      string
+		weekStart time.Weekday
+		weekdays  string
+		weeks     int
+		leading   int
+		trailing  int
+	}{
+		{"monday", time.Monday, "Mon Tue Wed Thu Fri Sat Sun", 6, 6, 5},
+		{"sunday", time.Sunday, "Sun Mon Tue Wed Thu Fri Sat", 5, 0, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal := calendar(report, 8*time.Hour, tt.weekStart, "")
+			if got := strings.Join(cal.Weekdays, " "); got != tt.weekdays {
+				t.Errorf("calendar() weekdays = %q, want %q", got, tt.weekdays)
+			}
+			if len(cal.Weeks) != tt.weeks {
+				t.Fatalf("calendar() has %d weeks, want %d", len(cal.Weeks), tt.weeks)
+			}
+			leading, trailing := 0, 0
+			for _, d := range cal.Weeks[0] {
+				if !d.InRange {
+					leading++
+				}
+			}
+			for _, d := range cal.Weeks[len(cal.Weeks)-1] {
+				if !d.InRange {
+					trailing++
+				}
+			}
+			if leading != tt.leading || trailing != tt.trailing {
+				t.Errorf("calendar() pads with %d leading and %d trailing days, want %d and %d", leading, trailing, tt.leading, tt.trailing)
+			}
+			if pad := cal.Weeks[0][0]; tt.leading > 0 && (pad.Cell() != "" || pad.Over() || pad.Under()) {
+				t.Errorf("padding cell = %q over %v under %v, want an empty cell", pad.Cell(), pad.Over(), pad.Under())
+			}
+			if first := cal.Weeks[0][tt.leading]; !first.Date.Equal(day(1)) {
+				t.Errorf("first day in range = %s, want 2020-03-01", first.Date.Format("2006-01-02"))
+			}
+		})
+	}
+
+	cells := map[string]CalendarDay{}
+	for _, week := range calendar(report, 8*time.Hour, time.Monday, "").Weeks {
+		for _, d := range week {
+			if d.InRange {
+				cells[d.Date.Format("2006-01-02")] = d
+			}
+		}
+	}
+	days := []struct {
+		date  string
+		cell  string
+		over  bool
+		under bool
+	}{
+		{"2020-03-01", "1 -", false, false},
+		{"2020-03-02", "2 9.0h", true, false},
+		{"2020-03-03", "3 4.0h", false, true},
+		{"2020-03-04", "4 8.0h", false, false},
+		{"2020-03-05", "5 -", false, true},
+		{"2020-03-07", "7 2.0h", false, false},
+	}
+	for _, tt := range days {
+		d := cells[tt.date]
+		if d.Cell() != tt.cell || d.Over() != tt.over || d.Under() != tt.under {
+			t.Errorf("%s = %q over %v under %v, want %q over %v under %v", tt.date, d.Cell(), d.Over(), d.Under(), tt.cell, tt.over, tt.under)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package backend
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DateOrder values choose how numeric dates such as 01/02/2006 are read
+const (
+	DateOrderMDY = "mdy"
+	DateOrderDMY = "dmy"
+)
+
+// dateLayouts returns the layouts accepted for report dates, in the
+// order they are tried.  Both orders of day and month are accepted for
+// slashed dates, but the preferred order wins when a date like 01/02/2006
+// is valid either way.
+func dateLayouts(order string) []string {
+	mdy, dmy := "1/2/2006", "2/1/2006"
+	slashed := []string{mdy, dmy}
+	if order == DateOrderDMY {
+		slashed = []string{dmy, mdy}
+	}
+	layouts := []string{
+		"2006-1-2", // should support optional leading zeros
+		"2006-01-02T15:04:05-07:00",
+	}
+	layouts = append(layouts, slashed...)
+	return append(layouts,
+		"2.1.2006",
+		"Jan 2 2006",
+		"Jan 2, 2006",
+		"January 2 2006",
+		"January 2, 2006",
+		"2 Jan 2006",
+		"2 January 2006",
+	)
+}
+
+// inZone shows the times of entries in loc, leaving durations and the
+// dates used to group them unchanged
+func inZone(entries []ReportEntry, loc *time.Location) {
+	for i := range entries {
+		entries[i].Start = entries[i].Start.In(loc)
+		entries[i].End = entries[i].End.In(loc)
+		entries[i].Ts = entries[i].Ts.In(loc)
+	}
+}
+
+// parseDate reads a report date in any of the accepted layouts
+func parseDate(s, order string, loc *time.Location) (time.Time, error) {
+	layouts := dateLayouts(order)
+	for _, layout := range layouts {
+		t, err := time.ParseInLocation(layout, s, loc)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.Errorf("%q doesn't match any accepted format: %s", s, strings.Join(layouts, ", "))
+}
@@ -0,0 +1,36 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		s     string
+		order string
+		want  time.Time
+	}{
+		{"2020-1-2", DateOrderMDY, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"01/02/2020", DateOrderMDY, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"01/02/2020", DateOrderDMY, time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"13/02/2020", DateOrderMDY, time.Date(2020, 2, 13, 0, 0, 0, 0, time.UTC)},
+		{"2.1.2020", DateOrderMDY, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"Jan 2, 2020", DateOrderDMY, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"2 January 2020", DateOrderMDY, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s+" "+tt.order, func(t *testing.T) {
+			got, err := parseDate(tt.s, tt.order, time.UTC)
+			if err != nil {
+				t.Fatalf("parseDate() error = %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, err := parseDate("yesterday", DateOrderMDY, time.UTC); err == nil {
+		t.Error("parseDate() accepted an invalid date")
+	}
+}
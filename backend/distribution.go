@@ -0,0 +1,54 @@
+package backend
+
+import (
+	"strings"
+	"time"
+)
+
+// DistributionTemplateString defines the template used to output a
+// Report() with FormatText when an hour of day distribution is requested
+var DistributionTemplateString = `Task Hours by Hour of Day: {{.From.Format "2006-01-02"}} to {{(.To.AddDate 0 0 -1).Format "2006-01-02"}}
+{{range $hour, $d := .Distribution}}
+{{printf "%02d:00" $hour}} {{bar $d $.Distribution}} {{$d}}
+{{- end}}
+`
+
+// barWidth is the length of the longest bar in a distribution
+const barWidth = 40
+
+// distribution totals the task time of entries falling in each hour of
+// the day, splitting entries that span several hours between them
+func distribution(entries []ReportEntry) []time.Duration {
+	hours := make([]time.Duration, 24)
+	for _, entry := range entries {
+		if entry.Brk || entry.Ignore || entry.Unaccounted {
+			continue
+		}
+		start := entry.Start
+		end := entry.Start.Add(entry.Duration)
+		for start.Before(end) {
+			next := start.Truncate(time.Hour).Add(time.Hour)
+			if next.After(end) {
+				next = end
+			}
+			hours[start.Hour()] += next.Sub(start)
+			start = next
+		}
+	}
+	return hours
+}
+
+// bar draws d as a bar scaled against the largest value in all
+func bar(d time.Duration, all []time.Duration) string {
+	var max time.Duration
+	for _, v := range all {
+		if v > max {
+			max = v
+		}
+	}
+	if max == 0 {
+		return ""
+	}
+	n := int(int64(d) * barWidth / int64(max))
+	return strings.Repeat("#", n) + strings.Repeat(" ", barWidth-n)
+}
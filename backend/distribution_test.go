@@ -0,0 +1,56 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_distribution(t *testing.T) {
+	at := func(hour, min int) time.Time {
+		return time.Date(2020, 3, 2, hour, min, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name    string
+		entries []ReportEntry
+		want    map[int]time.Duration
+	}{
+		{"within an hour", []ReportEntry{{Start: at(9, 10), Duration: 20 * time.Minute}},
+			map[int]time.Duration{9: 20 * time.Minute}},
+		{"split across hours", []ReportEntry{{Start: at(9, 45), Duration: 90 * time.Minute}},
+			map[int]time.Duration{9: 15 * time.Minute, 10: time.Hour, 11: 15 * time.Minute}},
+		{"past midnight", []ReportEntry{{Start: at(23, 30), Duration: time.Hour}},
+			map[int]time.Duration{23: 30 * time.Minute, 0: 30 * time.Minute}},
+		{"breaks, ignored and unaccounted left out", []ReportEntry{
+			{Start: at(12, 0), Duration: time.Hour, Brk: true},
+			{Start: at(13, 0), Duration: time.Hour, Ignore: true},
+			{Start: at(14, 0), Duration: time.Hour, Unaccounted: true},
+		}, map[int]time.Duration{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := distribution(tt.entries)
+			if len(got) != 24 {
+				t.Fatalf("distribution() has %d hours, want 24", len(got))
+			}
+			for hour, d := range got {
+				if d != tt.want[hour] {
+					t.Errorf("distribution() hour %d = %s, want %s", hour, d, tt.want[hour])
+				}
+			}
+		})
+	}
+}
+
+func Test_bar(t *testing.T) {
+	all := []time.Duration{time.Hour, 30 * time.Minute, 0}
+	for i, want := range []int{barWidth, barWidth / 2, 0} {
+		got := bar(all[i], all)
+		if len(got) != barWidth || strings.Count(got, "#") != want {
+			t.Errorf("bar(%s) = %q, want %d of %d filled", all[i], got, want, barWidth)
+		}
+	}
+	if got := bar(0, make([]time.Duration, 24)); got != "" {
+		t.Errorf("bar() with nothing tracked = %q, want empty", got)
+	}
+}
@@ -0,0 +1,45 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultGreeting opens report emails unless one is configured
+const DefaultGreeting = "Hi,"
+
+// Email holds the [email] settings used to wrap reports for sending
+type Email struct {
+	// To is the recipient for the To: header, left out if empty
+	To string
+	// Name signs off the email
+	Name     string
+	Greeting string
+}
+
+// emailReport wraps a formatted report in an email-ready block with a
+// suggested subject naming the report range, a greeting and a sign-off
+func (b *Backend) emailReport(report Report, body string) string {
+	settings := b.config.settings.Email
+	last := report.To.AddDate(0, 0, -1)
+	period := report.From.Format("2006-01-02")
+	if !sameDay(report.From, last) {
+		period += " to " + last.Format("2006-01-02")
+	}
+	greeting := settings.Greeting
+	if greeting == "" {
+		greeting = DefaultGreeting
+	}
+	var sb strings.Builder
+	if settings.To != "" {
+		fmt.Fprintf(&sb, "To: %s\n", settings.To)
+	}
+	fmt.Fprintf(&sb, "Subject: Time report %s (%s)\n\n", period, report.TaskHrs)
+	fmt.Fprintf(&sb, "%s\n\nHere is my time report for %s.\n\n", greeting, period)
+	sb.WriteString(strings.TrimSpace(body))
+	sb.WriteString("\n\nBest regards,\n")
+	if settings.Name != "" {
+		sb.WriteString(settings.Name + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,64 @@
+package backend
+
+import (
+	"os"
+)
+
+// ErrorCode classifies an error so that scripts can branch on the kind of
+// failure instead of matching error messages
+type ErrorCode string
+
+const (
+	// CodeParse indicates invalid user input such as a malformed date
+	CodeParse ErrorCode = "parse_error"
+	// CodeLock indicates the timesheet is locked by another omw process
+	CodeLock ErrorCode = "lock_error"
+	// CodeNotFound indicates a missing file or entry
+	CodeNotFound ErrorCode = "not_found"
+	// CodeCorrupt indicates the timesheet can't be understood
+	CodeCorrupt ErrorCode = "corrupt_data"
+	// CodeUnknown is used for every other error
+	CodeUnknown ErrorCode = "error"
+)
+
+// codedError attaches an ErrorCode to an error
+type codedError struct {
+	code ErrorCode
+	err  error
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+// Cause lets errors.Cause() see through the code
+func (e *codedError) Cause() error {
+	return e.err
+}
+
+// withCode attaches code to err
+func withCode(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// Code returns the ErrorCode of the outermost coded error wrapped by err
+// Missing files are reported as CodeNotFound even without a code.
+func Code(err error) ErrorCode {
+	for err != nil {
+		if coded, ok := err.(*codedError); ok {
+			return coded.code
+		}
+		if os.IsNotExist(err) {
+			return CodeNotFound
+		}
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			break
+		}
+		err = cause.Cause()
+	}
+	return CodeUnknown
+}
@@ -0,0 +1,85 @@
+package backend
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// EstimatePrefix marks the token that records how long a task was
+// expected to take, for example est:2h or est:45m
+const EstimatePrefix = "est:"
+
+// EstimateTotal compares the estimated and actual hours of a task
+// Variance is actual minus estimate, so a positive variance means the
+// task overran its estimate
+type EstimateTotal struct {
+	Project  string        `json:"project,omitempty"`
+	Title    string        `json:"title"`
+	Estimate time.Duration `json:"estimate"`
+	Actual   time.Duration `json:"actual"`
+	Variance time.Duration `json:"variance"`
+}
+
+// parseEstimate returns the duration of an est: token
+func parseEstimate(word string) (time.Duration, error) {
+	d, err := time.ParseDuration(strings.TrimPrefix(word, EstimatePrefix))
+	if err != nil {
+		return 0, withCode(CodeParse, errors.Wrapf(err, "invalid estimate %q", word))
+	}
+	if d <= 0 {
+		return 0, withCode(CodeParse, errors.Errorf("invalid estimate %q: must be positive", word))
+	}
+	return d, nil
+}
+
+// validateEstimates checks every est: token in a task before it is saved
+func validateEstimates(task string) error {
+	for _, word := range strings.Fields(task) {
+		if !strings.HasPrefix(word, EstimatePrefix) {
+			continue
+		}
+		if _, err := parseEstimate(word); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// estimateTotals sums the actual hours of each project and title that
+// has an estimate
+// A task logged across several entries only needs its estimate once; if
+// the entries disagree the largest estimate is used.
+func estimateTotals(entries []ReportEntry) []EstimateTotal {
+	totals := map[string]*EstimateTotal{}
+	keys := []string{}
+	for _, entry := range entries {
+		if entry.Brk || entry.Ignore || entry.Unaccounted {
+			continue
+		}
+		key := entry.Project + "\x00" + entry.Title
+		total, ok := totals[key]
+		if !ok {
+			total = &EstimateTotal{Project: entry.Project, Title: entry.Title}
+			totals[key] = total
+			keys = append(keys, key)
+		}
+		total.Actual += entry.Duration
+		if entry.Estimate > total.Estimate {
+			total.Estimate = entry.Estimate
+		}
+	}
+	sort.Strings(keys)
+	estimates := []EstimateTotal{}
+	for _, key := range keys {
+		total := totals[key]
+		if total.Estimate == 0 {
+			continue
+		}
+		total.Variance = total.Actual - total.Estimate
+		estimates = append(estimates, *total)
+	}
+	return estimates
+}
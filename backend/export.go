@@ -0,0 +1,140 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ClockifyHeader lists the columns of Clockify's detailed report CSV
+var ClockifyHeader = []string{
+	"Project",
+	"Description",
+	"Start Date",
+	"Start Time",
+	"End Date",
+	"End Time",
+	"Duration (h)",
+	"Billable",
+}
+
+// formatClockify renders the tasks of a report as CSV that can be bulk
+// imported into Clockify
+// Breaks, ignored time, unaccounted gaps and zero-length entries such as
+// the first entry of each day are not tracked work, so they are left out.
+func formatClockify(report Report, billable func(*ReportEntry) bool) (string, error) {
+	rows := [][]string{ClockifyHeader}
+	for i := range report.Entries {
+		entry := &report.Entries[i]
+		if entry.Brk || entry.Ignore || entry.Unaccounted || entry.Duration == 0 {
+			continue
+		}
+		end := entry.Start.Add(entry.Duration)
+		isBillable := "No"
+		if billable(entry) {
+			isBillable = "Yes"
+		}
+		rows = append(rows, []string{
+			entry.Project,
+			entry.Title,
+			entry.Start.Format("01/02/2006"),
+			entry.Start.Format("03:04:05 PM"),
+			end.Format("01/02/2006"),
+			end.Format("03:04:05 PM"),
+			clockDuration(entry.Duration),
+			isBillable,
+		})
+	}
+	return writeCSV(rows)
+}
+
+// KimaiHeader lists the columns of Kimai's timesheet CSV import
+var KimaiHeader = []string{
+	"Date",
+	"From",
+	"To",
+	"Duration",
+	"Customer",
+	"Project",
+	"Activity",
+	"Description",
+}
+
+// formatKimai renders the tasks of a report as CSV that can be imported
+// into Kimai, with each !client as the customer and the title as both the
+// activity and the description
+// Like formatClockify() it leaves out breaks, ignored time, unaccounted
+// gaps and zero-length entries.
+func formatKimai(report Report) (string, error) {
+	rows := [][]string{KimaiHeader}
+	for i := range report.Entries {
+		entry := &report.Entries[i]
+		if entry.Brk || entry.Ignore || entry.Unaccounted || entry.Duration == 0 {
+			continue
+		}
+		end := entry.Start.Add(entry.Duration)
+		rows = append(rows, []string{
+			entry.Start.Format("2006-01-02"),
+			entry.Start.Format("15:04"),
+			end.Format("15:04"),
+			clockDuration(entry.Duration),
+			entry.Client,
+			entry.Project,
+			entry.Title,
+			entry.Title,
+		})
+	}
+	return writeCSV(rows)
+}
+
+// CSVHeader lists the columns of FormatCSV
+var CSVHeader = []string{
+	"ID",
+	"Start",
+	"End",
+	"Hours",
+	"Title",
+	"Break",
+	"Ignore",
+}
+
+// formatCSV renders every entry of a report as a CSV row, with its
+// duration in decimal hours so spreadsheets can sum it
+// The header is written even if there are no entries.
+func formatCSV(report Report) (string, error) {
+	rows := [][]string{CSVHeader}
+	for _, entry := range report.Entries {
+		rows = append(rows, []string{
+			entry.ID,
+			entry.Start.Format(time.RFC3339),
+			entry.Start.Add(entry.Duration).Format(time.RFC3339),
+			strconv.FormatFloat(entry.Duration.Hours(), 'f', 2, 64),
+			entry.Title,
+			strconv.FormatBool(entry.Brk),
+			strconv.FormatBool(entry.Ignore),
+		})
+	}
+	return writeCSV(rows)
+}
+
+// writeCSV renders rows as RFC 4180 CSV, quoting fields as needed
+func writeCSV(rows [][]string) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	err := w.WriteAll(rows)
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// clockDuration formats d as H:MM:SS
+func clockDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	s := (d % time.Minute) / time.Second
+	return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+}
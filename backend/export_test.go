@@ -0,0 +1,88 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_formatCSV(t *testing.T) {
+	start := time.Date(2020, 3, 2, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		entries []ReportEntry
+		want    string
+	}{
+		{"header without entries", nil, "ID,Start,End,Hours,Title,Break,Ignore\n"},
+		{"quoted title", []ReportEntry{{ID: "1", Start: start, Duration: 90 * time.Minute, Title: "review, then merge"}},
+			"ID,Start,End,Hours,Title,Break,Ignore\n1,2020-03-02T09:00:00Z,2020-03-02T10:30:00Z,1.50,\"review, then merge\",false,false\n"},
+		{"break", []ReportEntry{{ID: "2", Start: start, Duration: 20 * time.Minute, Title: "coffee", Brk: true}},
+			"ID,Start,End,Hours,Title,Break,Ignore\n2,2020-03-02T09:00:00Z,2020-03-02T09:20:00Z,0.33,coffee,true,false\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatCSV(Report{Entries: tt.entries})
+			if err != nil || got != tt.want {
+				t.Errorf("formatCSV() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_formatClockify(t *testing.T) {
+	start := time.Date(2020, 3, 2, 23, 30, 0, 0, time.UTC)
+	header := "Project,Description,Start Date,Start Time,End Date,End Time,Duration (h),Billable\n"
+	billable := func(entry *ReportEntry) bool { return entry.Project == "acme" }
+	tests := []struct {
+		name    string
+		entries []ReportEntry
+		want    string
+	}{
+		{"header without entries", nil, header},
+		{"past midnight", []ReportEntry{{Start: start, Duration: 90 * time.Minute, Title: "deploy", Project: "acme"}},
+			header + "acme,deploy,03/02/2020,11:30:00 PM,03/03/2020,01:00:00 AM,1:30:00,Yes\n"},
+		{"not billable", []ReportEntry{{Start: start, Duration: 45 * time.Second, Title: "email"}},
+			header + ",email,03/02/2020,11:30:00 PM,03/02/2020,11:30:45 PM,0:00:45,No\n"},
+		{"breaks, ignored, unaccounted and zero-length entries left out", []ReportEntry{
+			{Start: start, Duration: time.Hour, Title: "lunch", Brk: true},
+			{Start: start, Duration: time.Hour, Title: "reading", Ignore: true},
+			{Start: start, Duration: time.Hour, Title: "unaccounted", Unaccounted: true},
+			{Start: start, Title: "hello"},
+		}, header},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatClockify(Report{Entries: tt.entries}, billable)
+			if err != nil || got != tt.want {
+				t.Errorf("formatClockify() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_formatKimai(t *testing.T) {
+	start := time.Date(2020, 3, 2, 9, 15, 0, 0, time.UTC)
+	header := "Date,From,To,Duration,Customer,Project,Activity,Description\n"
+	tests := []struct {
+		name    string
+		entries []ReportEntry
+		want    string
+	}{
+		{"header without entries", nil, header},
+		{"client and project", []ReportEntry{{Start: start, Duration: 105 * time.Minute, Title: "api, v2", Project: "backend", Client: "acme"}},
+			header + "2020-03-02,09:15,11:00,1:45:00,acme,backend,\"api, v2\",\"api, v2\"\n"},
+		{"breaks, ignored, unaccounted and zero-length entries left out", []ReportEntry{
+			{Start: start, Duration: time.Hour, Title: "lunch", Brk: true},
+			{Start: start, Duration: time.Hour, Title: "reading", Ignore: true},
+			{Start: start, Duration: time.Hour, Title: "unaccounted", Unaccounted: true},
+			{Start: start, Title: "hello"},
+		}, header},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatKimai(Report{Entries: tt.entries})
+			if err != nil || got != tt.want {
+				t.Errorf("formatKimai() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
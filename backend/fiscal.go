@@ -0,0 +1,58 @@
+package backend
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ParseMonth converts a month name such as "april" or "Apr", or a month
+// number such as "4", into a time.Month
+func ParseMonth(s string) (time.Month, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if n, err := strconv.Atoi(name); err == nil && n >= 1 && n <= 12 {
+		return time.Month(n), nil
+	}
+	for m := time.January; m <= time.December; m++ {
+		month := strings.ToLower(m.String())
+		if name == month || (len(name) >= 3 && strings.HasPrefix(month, name)) {
+			return m, nil
+		}
+	}
+	return time.January, errors.Errorf("invalid month %q - expected a month name such as april or a number from 1 to 12", s)
+}
+
+// ParseQuarter converts a fiscal quarter such as "Q1" or "3" into its
+// number
+func ParseQuarter(s string) (int, error) {
+	q, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "Q"))
+	if err != nil || q < 1 || q > 4 {
+		return 0, errors.Errorf("invalid fiscal quarter %q - expected Q1, Q2, Q3 or Q4", s)
+	}
+	return q, nil
+}
+
+// FiscalYear returns the fiscal year containing t, for fiscal years that
+// begin in start
+// A fiscal year is named after the calendar year it begins in, so with an
+// April start fiscal year 2024 runs from April 2024 to March 2025.
+func FiscalYear(t time.Time, start time.Month) int {
+	if t.Month() < start {
+		return t.Year() - 1
+	}
+	return t.Year()
+}
+
+// FiscalRange returns the first and last day of a fiscal year, or of one
+// of its quarters if quarter is between 1 and 4
+func FiscalRange(year, quarter int, start time.Month, loc *time.Location) (time.Time, time.Time) {
+	first := time.Date(year, start, 1, 0, 0, 0, 0, loc)
+	months := 12
+	if quarter >= 1 && quarter <= 4 {
+		first = first.AddDate(0, 3*(quarter-1), 0)
+		months = 3
+	}
+	return first, first.AddDate(0, months, -1)
+}
@@ -0,0 +1,91 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFiscalRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		year    int
+		quarter int
+		start   time.Month
+		first   string
+		last    string
+	}{
+		{"calendar year", 2024, 0, time.January, "2024-01-01", "2024-12-31"},
+		{"april year", 2024, 0, time.April, "2024-04-01", "2025-03-31"},
+		{"april Q1", 2024, 1, time.April, "2024-04-01", "2024-06-30"},
+		{"april Q4 crosses the calendar year", 2024, 4, time.April, "2025-01-01", "2025-03-31"},
+		{"october Q2", 2019, 2, time.October, "2020-01-01", "2020-03-31"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last := FiscalRange(tt.year, tt.quarter, tt.start, time.UTC)
+			if got := first.Format("2006-01-02"); got != tt.first {
+				t.Errorf("FiscalRange() first = %s, want %s", got, tt.first)
+			}
+			if got := last.Format("2006-01-02"); got != tt.last {
+				t.Errorf("FiscalRange() last = %s, want %s", got, tt.last)
+			}
+		})
+	}
+}
+
+func TestFiscalYear(t *testing.T) {
+	tests := []struct {
+		date  time.Time
+		start time.Month
+		want  int
+	}{
+		{time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), time.April, 2023},
+		{time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), time.April, 2024},
+		{time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), time.January, 2024},
+	}
+	for _, tt := range tests {
+		if got := FiscalYear(tt.date, tt.start); got != tt.want {
+			t.Errorf("FiscalYear(%s, %s) = %d, want %d", tt.date.Format("2006-01-02"), tt.start, got, tt.want)
+		}
+	}
+}
+
+func TestParseQuarter(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    int
+		wantErr bool
+	}{
+		{"Q1", 1, false},
+		{"q4", 4, false},
+		{"3", 3, false},
+		{"Q5", 0, true},
+		{"first", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseQuarter(tt.s)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseQuarter(%q) = %d, %v, want %d, wantErr %v", tt.s, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseMonth(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    time.Month
+		wantErr bool
+	}{
+		{"april", time.April, false},
+		{"Apr", time.April, false},
+		{"10", time.October, false},
+		{"ju", time.January, true},
+		{"13", time.January, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMonth(tt.s)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseMonth(%q) = %s, %v, want %s, wantErr %v", tt.s, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
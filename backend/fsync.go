@@ -0,0 +1,46 @@
+package backend
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// writeFileSync is ioutil.WriteFile followed by an fsync, so the data is
+// on disk and not just in the page cache when it returns
+func writeFileSync(fn string, data []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(fn, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// renameSync renames oldpath over newpath and flushes the directory, so
+// the rename itself survives a crash
+func renameSync(oldpath, newpath string) error {
+	err := os.Rename(oldpath, newpath)
+	if err != nil {
+		return err
+	}
+	syncDir(filepath.Dir(newpath))
+	return nil
+}
+
+// syncDir flushes the entries of dir
+// Not every platform can open a directory for syncing, so this is best
+// effort and errors are ignored.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
@@ -0,0 +1,88 @@
+package backend
+
+import (
+	"sort"
+	"time"
+)
+
+// UnaccountedTitle labels the entries added by ReportOptions.FillGaps
+const UnaccountedTitle = "[unaccounted]"
+
+// fillGaps adds unaccounted entries to a report for the parts of each
+// weekday's working window, dayStart to dayEnd after midnight, that are
+// not covered by any timesheet entry
+// stamps holds the timestamp of every entry in the report period before
+// filtering, so filtered tasks are not mistaken for missing time.  Tracked
+// time runs from the first to the last entry of each day.
+func fillGaps(report *Report, stamps []time.Time, dayStart, dayEnd time.Duration) {
+	first := make(map[string]time.Time)
+	last := make(map[string]time.Time)
+	for _, ts := range stamps {
+		key := ts.Format("2006-01-02")
+		if f, ok := first[key]; !ok || ts.Before(f) {
+			first[key] = ts
+		}
+		if l, ok := last[key]; !ok || ts.After(l) {
+			last[key] = ts
+		}
+	}
+
+	now := time.Now()
+	gaps := []ReportEntry{}
+	for date := report.From; date.Before(report.To); date = date.AddDate(0, 0, 1) {
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			continue
+		}
+		y, m, d := date.Date()
+		midnight := time.Date(y, m, d, 0, 0, 0, 0, date.Location())
+		start := midnight.Add(dayStart)
+		end := midnight.Add(dayEnd)
+		if now.Before(end) {
+			end = now
+		}
+		key := date.Format("2006-01-02")
+		f, ok := first[key]
+		if !ok {
+			gaps = appendGap(gaps, start, end)
+			continue
+		}
+		// tracked time outside the working window leaves the whole
+		// window, or the part after or before it, unaccounted
+		gaps = appendGap(gaps, start, within(f, start, end))
+		gaps = appendGap(gaps, within(last[key], start, end), end)
+	}
+
+	for _, gap := range gaps {
+		report.UnaccountedHrs += gap.Duration
+	}
+	report.Entries = append(report.Entries, gaps...)
+	sort.SliceStable(report.Entries, func(i, j int) bool {
+		return report.Entries[i].Ts.Before(report.Entries[j].Ts)
+	})
+}
+
+// within returns t limited to the window from start to end
+func within(t, start, end time.Time) time.Time {
+	if t.Before(start) {
+		return start
+	}
+	if t.After(end) {
+		return end
+	}
+	return t
+}
+
+// appendGap adds an unaccounted entry from start to end if it is not empty
+func appendGap(gaps []ReportEntry, start, end time.Time) []ReportEntry {
+	if !end.After(start) {
+		return gaps
+	}
+	return append(gaps, ReportEntry{
+		Title:       UnaccountedTitle,
+		Unaccounted: true,
+		Start:       start,
+		End:         start,
+		Ts:          end,
+		Duration:    end.Sub(start),
+	})
+}
@@ -0,0 +1,124 @@
+package backend
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// GroupKeys are the keys ReportOptions.GroupBy accepts
+var GroupKeys = []string{"project", "client", "day", "week", "tag"}
+
+// GroupTotal describes the task hours of one group of entries, such as a
+// project, along with the subtotals of the groups nested within it
+type GroupTotal struct {
+	Key      string        `json:"key"`
+	Name     string        `json:"name"`
+	TaskHrs  time.Duration `json:"taskTotalHours"`
+	Children []*GroupTotal `json:"children,omitempty"`
+	Indent   string        `json:"-"`
+}
+
+// validGroupBy checks the keys of ReportOptions.GroupBy
+func validGroupBy(keys []string) error {
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if !contains(GroupKeys, key) {
+			return withCode(CodeParse, errors.Errorf("can't group by %q - expected one of %s", key, strings.Join(GroupKeys, ", ")))
+		}
+		if seen[key] {
+			return withCode(CodeParse, errors.Errorf("can't group by %q twice", key))
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// groupNames returns the groups an entry belongs to for key
+// An entry with several tags is counted under each of them, so the tag
+// subtotals can add up to more than their parent.
+func groupNames(entry *ReportEntry, key string, weekStart time.Weekday) []string {
+	switch key {
+	case "project":
+		if entry.Project == "" {
+			return []string{"(no project)"}
+		}
+		return []string{entry.Project}
+	case "client":
+		if entry.Client == "" {
+			return []string{"(no client)"}
+		}
+		return []string{entry.Client}
+	case "day":
+		return []string{entry.Ts.Format("2006-01-02")}
+	case "week":
+		first, _ := WeekRange(entry.Ts, weekStart)
+		return []string{"week of " + first.Format("2006-01-02")}
+	case "tag":
+		if len(entry.Tags) == 0 {
+			return []string{"(no tag)"}
+		}
+		return entry.Tags
+	}
+	return nil
+}
+
+// groupTotals sums the task hours of entries into nested groups, one
+// level for each of keys in order, sorted by name at each level
+func groupTotals(entries []ReportEntry, keys []string, weekStart time.Weekday) []*GroupTotal {
+	root := &GroupTotal{}
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Brk || entry.Ignore || entry.Unaccounted {
+			continue
+		}
+		root.add(entry, keys, 0, weekStart)
+	}
+	root.sort()
+	return root.Children
+}
+
+// add counts entry in the groups below g for keys[depth:]
+func (g *GroupTotal) add(entry *ReportEntry, keys []string, depth int, weekStart time.Weekday) {
+	if depth == len(keys) {
+		return
+	}
+	key := keys[depth]
+	for _, name := range groupNames(entry, key, weekStart) {
+		child := g.subgroup(key, name, depth)
+		child.TaskHrs += entry.Duration
+		child.add(entry, keys, depth+1, weekStart)
+	}
+}
+
+// subgroup returns the group directly below g called name, adding it at
+// depth if it isn't there yet
+func (g *GroupTotal) subgroup(key, name string, depth int) *GroupTotal {
+	child := g.child(name)
+	if child == nil {
+		child = &GroupTotal{Key: key, Name: name, Indent: strings.Repeat("  ", depth)}
+		g.Children = append(g.Children, child)
+	}
+	return child
+}
+
+// child returns the group directly below g called name, or nil
+func (g *GroupTotal) child(name string) *GroupTotal {
+	for _, c := range g.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (g *GroupTotal) sort() {
+	sort.Slice(g.Children, func(i, j int) bool {
+		return g.Children[i].Name < g.Children[j].Name
+	})
+	for _, c := range g.Children {
+		c.sort()
+	}
+}
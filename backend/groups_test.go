@@ -0,0 +1,80 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_groupTotals(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2020, 3, d, 12, 0, 0, 0, time.UTC)
+	}
+	entries := []ReportEntry{
+		{Title: "api", Project: "acme", Duration: time.Hour, Ts: day(2)},
+		{Title: "lunch", Brk: true, Duration: time.Hour, Ts: day(2)},
+		{Title: "docs", Project: "omw", Duration: 2 * time.Hour, Ts: day(2)},
+		{Title: "api", Project: "acme", Duration: 3 * time.Hour, Ts: day(3)},
+		{Title: "email", Duration: time.Hour, Ts: day(3)},
+	}
+	groups := groupTotals(entries, []string{"project", "day"}, time.Monday)
+	want := []struct {
+		name  string
+		total time.Duration
+		days  int
+	}{
+		{"(no project)", time.Hour, 1},
+		{"acme", 4 * time.Hour, 2},
+		{"omw", 2 * time.Hour, 1},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("groupTotals() returned %d groups, want %d", len(groups), len(want))
+	}
+	for i, w := range want {
+		g := groups[i]
+		if g.Name != w.name || g.TaskHrs != w.total || len(g.Children) != w.days {
+			t.Errorf("group %d = %s %s with %d days, want %s %s with %d days", i, g.Name, g.TaskHrs, len(g.Children), w.name, w.total, w.days)
+		}
+	}
+	if d := groups[1].Children[1]; d.Name != "2020-03-03" || d.TaskHrs != 3*time.Hour || d.Indent != "  " {
+		t.Errorf("acme day 2 = %q %s indent %q, want 2020-03-03 3h0m0s indent two spaces", d.Name, d.TaskHrs, d.Indent)
+	}
+}
+
+func Test_projectTree(t *testing.T) {
+	entries := []ReportEntry{
+		{Title: "api", Project: "acme/backend/api", Duration: time.Hour},
+		{Title: "lunch", Brk: true, Duration: time.Hour},
+		{Title: "ui", Project: "acme/frontend", Duration: 2 * time.Hour},
+		{Title: "docs", Project: "omw", Duration: 30 * time.Minute},
+		{Title: "email", Duration: time.Hour},
+		{Title: "untracked", Unaccounted: true, Project: "omw", Duration: time.Hour},
+	}
+	tree := projectTree(entries)
+	if len(tree) != 2 || tree[0].Name != "acme" || tree[0].TaskHrs != 3*time.Hour || tree[1].Name != "omw" || tree[1].TaskHrs != 30*time.Minute {
+		t.Fatalf("projectTree() = %v, want acme 3h and omw 30m", tree)
+	}
+	backend := tree[0].Children[0]
+	if backend.Name != "backend" || backend.Key != "project" || backend.TaskHrs != time.Hour || backend.Indent != "  " {
+		t.Errorf("acme/backend = %+v, want backend 1h indented two spaces", backend)
+	}
+	if api := backend.Children[0]; api.Name != "api" || api.Indent != "    " {
+		t.Errorf("acme/backend/api = %+v, want api indented four spaces", api)
+	}
+}
+
+func Test_validGroupBy(t *testing.T) {
+	tests := []struct {
+		keys    []string
+		wantErr bool
+	}{
+		{[]string{"project", "day"}, false},
+		{[]string{"client", "week", "tag"}, false},
+		{[]string{"month"}, true},
+		{[]string{"day", "day"}, true},
+	}
+	for _, tt := range tests {
+		if err := validGroupBy(tt.keys); (err != nil) != tt.wantErr {
+			t.Errorf("validGroupBy(%v) error = %v, wantErr %v", tt.keys, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// reportHookTimeout bounds how long the report hook may run
+var reportHookTimeout = 30 * time.Second
+
+// runReportHook pipes the JSON report to the configured report hook and
+// returns what the hook writes to stdout as the report
+func (b *Backend) runReportHook(report Report) (string, error) {
+	hook := b.config.settings.ReportHook
+	input, err := b.formatReport(report, FormatJSON)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(b.ctx, reportHookTimeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, hook)
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return "", errors.Wrapf(err, "report hook %q can't be run - check that it exists and is executable", hook)
+	}
+	// a hook that leaves children behind holds stdout open after it is
+	// killed, so don't wait for them once the time is up
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		return "", errors.Errorf("report hook %q timed out after %s", hook, reportHookTimeout)
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return "", errors.Errorf("report hook %q exited with status %d", hook, exitErr.ExitCode())
+		}
+		return "", errors.Errorf("report hook %q exited with status %d: %s", hook, exitErr.ExitCode(), msg)
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "running report hook %q", hook)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,65 @@
+package backend
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackend_Report_hook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts need a shell")
+	}
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T10:00:00Z
+  task = "write docs"
+`
+	tests := []struct {
+		name    string
+		script  string
+		want    string
+		wantErr string
+	}{
+		{"stdout is the report", "#!/bin/sh\ngrep -o 'write docs' | head -n 1\n", "write docs", ""},
+		{"hook fails", "#!/bin/sh\necho broken >&2\nexit 3\n", "", "exited with status 3: broken"},
+		{"hook times out", "#!/bin/sh\nsleep 5\n", "", "timed out"},
+		{"hook not executable", "", "", "can't be run"},
+	}
+	defer func(d time.Duration) { reportHookTimeout = d }(reportHookTimeout)
+	reportHookTimeout = 500 * time.Millisecond
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, cleanup := newTestBackend(t, data)
+			defer cleanup()
+			hook := filepath.Join(b.config.omwDir, "hook.sh")
+			if tt.script != "" {
+				if err := ioutil.WriteFile(hook, []byte(tt.script), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			b.config.settings.ReportHook = hook
+			got, err := b.Report("2020-03-02", "2020-03-02", "text", ReportOptions{})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Backend.Report() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || strings.TrimSpace(got) != tt.want {
+				t.Errorf("Backend.Report() = %q, %v, want %q", got, err, tt.want)
+			}
+			got, _ = b.Report("2020-03-02", "2020-03-02", "text", ReportOptions{NoHook: true})
+			if !strings.Contains(got, "Total Task Hours") {
+				t.Errorf("Backend.Report() with NoHook = %q, want the text report", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package backend
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// icsTime is the UTC date-time form of iCalendar
+const icsTime = "20060102T150405Z"
+
+// icsEscaper escapes TEXT values as RFC 5545 requires
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// formatICS renders the entries of a report as an iCalendar VCALENDAR
+// with a VEVENT for each entry, stamped with now
+// Break and ignore entries are marked with CATEGORIES.  Unaccounted gaps
+// aren't events, and zero-length entries such as the first entry of each
+// day are left out.  Entries without an ID, such as break rollups, get a
+// UID made from their start and end.
+func formatICS(report Report, now time.Time) string {
+	var sb strings.Builder
+	line := func(s string) {
+		sb.WriteString(foldICS(s))
+		sb.WriteString("\r\n")
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//omw//omw report//EN")
+	line("CALSCALE:GREGORIAN")
+	for _, entry := range report.Entries {
+		if entry.Unaccounted || entry.Duration == 0 {
+			continue
+		}
+		start := entry.Start.UTC().Format(icsTime)
+		end := entry.Start.Add(entry.Duration).UTC().Format(icsTime)
+		uid := entry.ID
+		if uid == "" {
+			uid = start + "-" + end
+		}
+		line("BEGIN:VEVENT")
+		line("UID:" + icsEscaper.Replace(uid) + "@omw")
+		line("DTSTAMP:" + now.UTC().Format(icsTime))
+		line("DTSTART:" + start)
+		line("DTEND:" + end)
+		line("SUMMARY:" + icsEscaper.Replace(entry.Title))
+		if kind := entryType(entry); kind != "task" {
+			line("CATEGORIES:" + kind)
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return sb.String()
+}
+
+// foldICS breaks a content line into lines of at most 75 bytes, each
+// continuation starting with a space, without splitting characters
+func foldICS(s string) string {
+	const limit = 75
+	var sb strings.Builder
+	width := limit
+	for len(s) > width {
+		cut := width
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		sb.WriteString(s[:cut])
+		sb.WriteString("\r\n ")
+		s = s[cut:]
+		width = limit - 1
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
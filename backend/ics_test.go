@@ -0,0 +1,66 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_formatICS(t *testing.T) {
+	start := time.Date(2020, 3, 2, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+	now := time.Date(2020, 3, 3, 12, 0, 0, 0, time.UTC)
+	report := Report{Entries: []ReportEntry{
+		{ID: "first", Start: start},
+		{ID: "a1", Start: start, Duration: 90 * time.Minute, Title: "review, then merge"},
+		{ID: "a2", Start: start.Add(90 * time.Minute), Duration: 20 * time.Minute, Title: "coffee", Brk: true},
+		{Start: start.Add(110 * time.Minute), Duration: time.Hour, Title: UnaccountedTitle, Unaccounted: true},
+		{Start: start.Add(170 * time.Minute), Duration: 30 * time.Minute, Title: "Breaks", Brk: true, Rollup: 2},
+	}}
+	want := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"PRODID:-//omw//omw report//EN\r\n" +
+		"CALSCALE:GREGORIAN\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:a1@omw\r\n" +
+		"DTSTAMP:20200303T120000Z\r\n" +
+		"DTSTART:20200302T080000Z\r\n" +
+		"DTEND:20200302T093000Z\r\n" +
+		"SUMMARY:review\\, then merge\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:a2@omw\r\n" +
+		"DTSTAMP:20200303T120000Z\r\n" +
+		"DTSTART:20200302T093000Z\r\n" +
+		"DTEND:20200302T095000Z\r\n" +
+		"SUMMARY:coffee\r\n" +
+		"CATEGORIES:break\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:20200302T105000Z-20200302T112000Z@omw\r\n" +
+		"DTSTAMP:20200303T120000Z\r\n" +
+		"DTSTART:20200302T105000Z\r\n" +
+		"DTEND:20200302T112000Z\r\n" +
+		"SUMMARY:Breaks\r\n" +
+		"CATEGORIES:break\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	if got := formatICS(report, now); got != want {
+		t.Errorf("formatICS() = %q, want %q", got, want)
+	}
+}
+
+func Test_foldICS(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("é", 60)
+	got := foldICS(line)
+	for i, part := range strings.Split(got, "\r\n") {
+		if len(part) > 75 {
+			t.Errorf("foldICS() line %d is %d bytes", i, len(part))
+		}
+		if i > 0 && !strings.HasPrefix(part, " ") {
+			t.Errorf("foldICS() line %d doesn't start with a space", i)
+		}
+	}
+	if unfolded := strings.Replace(got, "\r\n ", "", -1); unfolded != line {
+		t.Errorf("foldICS() unfolds to %q, want %q", unfolded, line)
+	}
+}
@@ -0,0 +1,115 @@
+package backend
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// InvoiceTemplateString defines the template used to output a Report()
+// with FormatText when an invoice is requested
+var InvoiceTemplateString = `{{with .Invoice -}}
+INVOICE
+
+From: {{.Consultant.Name}}
+{{- if .Consultant.Address}}
+{{.Consultant.Address}}
+{{- end}}
+
+Bill To: {{.Client.Name}}
+{{- if .Client.Address}}
+{{.Client.Address}}
+{{- end}}
+
+Period: {{.From.Format "2006-01-02"}} to {{.To.Format "2006-01-02"}}
+
+Date          Hours          Rate        Amount  Description
+----------  -------  ------------  ------------  -----------
+{{range .Items -}}
+{{.Date.Format "2006-01-02"}}  {{printf "%7.2f" .Hours}}  {{money .Rate | pad 12}}  {{money .Amount | pad 12}}  {{.Description}}
+{{end -}}
+----------  -------  ------------  ------------  -----------
+Total       {{printf "%7.2f" .Hours}}                {{money .Total | pad 12}}
+{{end}}`
+
+// Party describes the consultant or client named on an invoice
+type Party struct {
+	Name    string  `json:"name"`
+	Address string  `json:"address,omitempty"`
+	Rate    float64 `json:"rate,omitempty"`
+}
+
+// Client describes a client configured under [clients.<key>]
+// Billable overrides the global billable default for the client's tasks
+type Client struct {
+	Party
+	Billable *bool
+}
+
+// Invoice describes the billable work done for a single client
+type Invoice struct {
+	Client     Party         `json:"client"`
+	Consultant Party         `json:"consultant"`
+	From       time.Time     `json:"from"`
+	To         time.Time     `json:"to"`
+	Items      []InvoiceItem `json:"items"`
+	Hours      float64       `json:"hours"`
+	Total      float64       `json:"total"`
+}
+
+// InvoiceItem is a single line of an invoice
+// Hours are decimal hours rounded to two places, and the amount is
+// calculated from the rounded hours so the lines add up to the total
+type InvoiceItem struct {
+	Date        time.Time `json:"date"`
+	Description string    `json:"description"`
+	Hours       float64   `json:"hours"`
+	Rate        float64   `json:"rate"`
+	Amount      float64   `json:"amount"`
+}
+
+// buildInvoice creates an invoice for client from the billable tasks and
+// paid breaks of a report that has already been filtered to that client
+// The client's rate is used if set, otherwise the consultant's.
+func (b *Backend) buildInvoice(report Report, client string) *Invoice {
+	settings := b.config.settings
+	invoice := &Invoice{
+		Client:     settings.Clients[strings.ToLower(client)].Party,
+		Consultant: settings.Consultant,
+		From:       report.From,
+		To:         report.To.Add(-24 * time.Hour),
+		Items:      []InvoiceItem{},
+	}
+	if invoice.Client.Name == "" {
+		invoice.Client.Name = client
+	}
+	rate := invoice.Consultant.Rate
+	if invoice.Client.Rate != 0 {
+		rate = invoice.Client.Rate
+	}
+	for i := range report.Entries {
+		entry := &report.Entries[i]
+		if (entry.Brk && !entry.Paid) || entry.Ignore || entry.Unaccounted || entry.Duration == 0 || !b.isBillable(entry) {
+			continue
+		}
+		hours := roundCents(entry.Duration.Hours())
+		item := InvoiceItem{
+			Date:        entry.Start,
+			Description: entry.Title,
+			Hours:       hours,
+			Rate:        rate,
+			Amount:      roundCents(hours * rate),
+		}
+		invoice.Items = append(invoice.Items, item)
+		invoice.Hours += item.Hours
+		invoice.Total += item.Amount
+	}
+	invoice.Hours = roundCents(invoice.Hours)
+	invoice.Total = roundCents(invoice.Total)
+	return invoice
+}
+
+// roundCents rounds f to two decimal places
+func roundCents(f float64) float64 {
+	return math.Round(f*100) / 100
+}
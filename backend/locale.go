@@ -0,0 +1,97 @@
+package backend
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is the language of report day headers unless configured
+const DefaultLocale = "en"
+
+// localeNames are the weekday names, Sunday first, and month names of a
+// language
+type localeNames struct {
+	days   [7]string
+	months [12]string
+}
+
+// locales holds the languages report day headers can be written in
+var locales = map[string]localeNames{
+	"en": {
+		[7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		[12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	},
+	"de": {
+		[7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		[12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+	},
+	"es": {
+		[7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		[12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+	},
+	"fr": {
+		[7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		[12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	},
+	"it": {
+		[7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		[12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+	},
+	"nl": {
+		[7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		[12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+	},
+	"pt": {
+		[7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		[12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+	},
+}
+
+// localeTags lists the languages of locales for matching, the default
+// first so it is the fallback
+var localeTags = []language.Tag{
+	language.English,
+	language.German,
+	language.Spanish,
+	language.French,
+	language.Italian,
+	language.Dutch,
+	language.Portuguese,
+}
+
+var localeMatcher = language.NewMatcher(localeTags)
+
+// ParseLocale matches a BCP 47 language tag such as "de" or "de-AT" to
+// one of the languages report headers can be written in
+func ParseLocale(s string) (string, error) {
+	tag, err := language.Parse(s)
+	if err != nil {
+		return DefaultLocale, withCode(CodeParse, errors.Wrapf(err, "invalid locale %q", s))
+	}
+	_, i, confidence := localeMatcher.Match(tag)
+	if confidence == language.No {
+		return DefaultLocale, withCode(CodeParse, errors.Errorf("unsupported locale %q", s))
+	}
+	base, _ := localeTags[i].Base()
+	return base.String(), nil
+}
+
+// weekdayName returns the name of the weekday of t in locale
+func weekdayName(locale string, t time.Time) string {
+	names, ok := locales[locale]
+	if !ok {
+		names = locales[DefaultLocale]
+	}
+	return names.days[t.Weekday()]
+}
+
+// monthName returns the name of the month of t in locale
+func monthName(locale string, t time.Time) string {
+	names, ok := locales[locale]
+	if !ok {
+		names = locales[DefaultLocale]
+	}
+	return names.months[t.Month()-1]
+}
@@ -0,0 +1,65 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+)
+
+// formatMarkdown renders the entries of a report as a Markdown table for
+// each day, under a bold day header, with the day's task, break and
+// ignore hours in a footer row
+// Zero-length entries such as the first entry of each day are left out.
+func formatMarkdown(report Report, locale string) string {
+	var sb strings.Builder
+	day := ""
+	var total DayTotal
+	footer := func() {
+		if day == "" {
+			return
+		}
+		fmt.Fprintf(&sb, "| **Total** | %s | break %s, ignore %s |\n", total.TaskHrs, total.BrkHrs, total.IgnoreHrs)
+	}
+	for _, entry := range report.Entries {
+		if entry.Duration == 0 {
+			continue
+		}
+		if d := entry.Ts.Format("2006-01-02"); d != day {
+			footer()
+			if day != "" {
+				sb.WriteString("\n")
+			}
+			day = d
+			total = DayTotal{}
+			fmt.Fprintf(&sb, "**%s, %s**\n\n", weekdayName(locale, entry.Ts), d)
+			sb.WriteString("| Time | Duration | Title |\n| --- | --- | --- |\n")
+		}
+		switch {
+		case entry.Brk:
+			total.BrkHrs += entry.Duration
+		case entry.Ignore:
+			total.IgnoreHrs += entry.Duration
+		case !entry.Unaccounted:
+			total.TaskHrs += entry.Duration
+		}
+		fmt.Fprintf(&sb, "| %s-%s | %s | %s |\n", entry.Start.Format("15:04"), entry.Start.Add(entry.Duration).Format("15:04"), entry.Duration, markdownTitle(entry))
+	}
+	footer()
+	return sb.String()
+}
+
+// markdownTitle returns the title of entry for a table cell, marking the
+// kinds of time that aren't tasks
+func markdownTitle(entry ReportEntry) string {
+	title := entry.Title
+	if entry.Rollup > 0 {
+		title = fmt.Sprintf("%s: %d entries", title, entry.Rollup)
+	}
+	if entry.Project != "" {
+		title += " @" + entry.Project
+	}
+	if kind := entryType(entry); kind != "task" {
+		title += " (" + kind + ")"
+	}
+	// a pipe would end the cell
+	return strings.Replace(title, "|", `\|`, -1)
+}
@@ -0,0 +1,42 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_formatMarkdown(t *testing.T) {
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2020, 3, day, hour, min, 0, 0, time.UTC)
+	}
+	report := Report{Entries: []ReportEntry{
+		{Title: "hello", Start: at(2, 9, 0), Ts: at(2, 9, 0)},
+		{Title: "api", Project: "acme", Start: at(2, 9, 0), Duration: 90 * time.Minute, Ts: at(2, 10, 30)},
+		{Title: "coffee", Brk: true, Start: at(2, 10, 30), Duration: 15 * time.Minute, Ts: at(2, 10, 45)},
+		{Title: "a|b", Ignore: true, Start: at(2, 10, 45), Duration: 15 * time.Minute, Ts: at(2, 11, 0)},
+		{Title: "hello", Start: at(3, 9, 0), Ts: at(3, 9, 0)},
+		{Title: "docs", Start: at(3, 9, 0), Duration: time.Hour, Ts: at(3, 10, 0)},
+	}}
+	want := `**Monday, 2020-03-02**
+
+| Time | Duration | Title |
+| --- | --- | --- |
+| 09:00-10:30 | 1h30m0s | api @acme |
+| 10:30-10:45 | 15m0s | coffee (break) |
+| 10:45-11:00 | 15m0s | a\|b (ignore) |
+| **Total** | 1h30m0s | break 15m0s, ignore 15m0s |
+
+**Tuesday, 2020-03-03**
+
+| Time | Duration | Title |
+| --- | --- | --- |
+| 09:00-10:00 | 1h0m0s | docs |
+| **Total** | 1h0m0s | break 0s, ignore 0s |
+`
+	if got := formatMarkdown(report, ""); got != want {
+		t.Errorf("formatMarkdown() = %q, want %q", got, want)
+	}
+	if got := formatMarkdown(Report{}, ""); got != "" {
+		t.Errorf("formatMarkdown() of an empty report = %q, want nothing", got)
+	}
+}
@@ -0,0 +1,170 @@
+package backend
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pelletier/go-toml"
+	"github.com/pkg/errors"
+)
+
+// MergeConflict describes two entries with the same ID but different
+// contents and which of them was kept
+type MergeConflict struct {
+	ID          string     `json:"id"`
+	Kept        SavedEntry `json:"kept"`
+	KeptFrom    string     `json:"keptFrom"`
+	Dropped     SavedEntry `json:"dropped"`
+	DroppedFrom string     `json:"droppedFrom"`
+}
+
+// MergeResult summarizes a MergeFiles() run
+type MergeResult struct {
+	Entries   int             `json:"entries"`
+	Conflicts []MergeConflict `json:"conflicts,omitempty"`
+}
+
+// timesheet is a timesheet file read for merging
+type timesheet struct {
+	path     string
+	modified time.Time
+	data     *SavedItems
+}
+
+// MergeFiles combines diverging copies of a timesheet, for example after
+// forgetting to sync one of them.  Entries are unioned by ID and sorted by
+// time.  When two entries share an ID but differ, the one that omw changed
+// last, by its Modified time, is kept; for entries saved before Modified
+// was recorded, the entry from the most recently modified file wins.
+// The result is checked with validateEdit() before it is saved to out, or
+// merged into the current timesheet if out is empty.
+func (b *Backend) MergeFiles(files []string, out string) (*MergeResult, error) {
+	sheets := []timesheet{}
+	for _, fn := range files {
+		sheet, err := loadTimesheet(fn)
+		if err != nil {
+			return nil, err
+		}
+		sheets = append(sheets, *sheet)
+	}
+	if out != "" {
+		data, result := mergeTimesheets(sheets)
+		data, err := validateMerge(filepath.Dir(out), data)
+		if err != nil {
+			return nil, err
+		}
+		sortEntries(data.Entries, b.config.settings.NewestFirst)
+		dataBytes, err := toml.Marshal(*data)
+		if err != nil {
+			return nil, errors.Wrap(err, "can't marshal data")
+		}
+		err = replaceFile(out, dataBytes)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	var result *MergeResult
+	err := b.updateEntries(func(current *SavedItems) (bool, error) {
+		info, err := os.Stat(b.config.omwFile)
+		if err != nil {
+			return false, err
+		}
+		own := timesheet{b.config.omwFile, info.ModTime(), current}
+		data, merged := mergeTimesheets(append([]timesheet{own}, sheets...))
+		data, err = validateMerge(filepath.Dir(b.config.omwFile), data)
+		if err != nil {
+			return false, err
+		}
+		*current = *data
+		result = merged
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// loadTimesheet reads a timesheet to merge along with its modification time
+func loadTimesheet(fn string) (*timesheet, error) {
+	info, err := os.Stat(fn)
+	if err != nil {
+		return nil, withCode(CodeNotFound, errors.Wrapf(err, "can't read %s", fn))
+	}
+	data, err := readTimesheet(fn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't read %s", fn)
+	}
+	return &timesheet{fn, info.ModTime(), data}, nil
+}
+
+// mergeTimesheets unions the entries of sheets by ID, oldest entry first
+func mergeTimesheets(sheets []timesheet) (*SavedItems, *MergeResult) {
+	result := &MergeResult{Conflicts: []MergeConflict{}}
+	kept := map[string]int{}
+	from := []timesheet{}
+	data := &SavedItems{}
+	for _, sheet := range sheets {
+		for _, e := range sheet.data.Entries {
+			i, exists := kept[e.ID]
+			if !exists {
+				kept[e.ID] = len(data.Entries)
+				data.Entries = append(data.Entries, e)
+				from = append(from, sheet)
+				continue
+			}
+			old := data.Entries[i]
+			if old.Task == e.Task && old.End.Equal(e.End) {
+				continue
+			}
+			conflict := MergeConflict{ID: e.ID, Kept: old, KeptFrom: from[i].path, Dropped: e, DroppedFrom: sheet.path}
+			newer := e.Modified.After(old.Modified)
+			if e.Modified.Equal(old.Modified) {
+				newer = sheet.modified.After(from[i].modified)
+			}
+			if newer {
+				conflict.Kept, conflict.Dropped = e, old
+				conflict.KeptFrom, conflict.DroppedFrom = sheet.path, from[i].path
+				data.Entries[i] = e
+				from[i] = sheet
+			}
+			result.Conflicts = append(result.Conflicts, conflict)
+		}
+	}
+	sortEntries(data.Entries, false)
+	result.Entries = len(data.Entries)
+	return data, result
+}
+
+// validateMerge runs validateEdit() over the merged data by way of a
+// temporary file in dir, just as if the user had written it with omw edit
+func validateMerge(dir string, data *SavedItems) (*SavedItems, error) {
+	dataBytes, err := toml.Marshal(*data)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't marshal data")
+	}
+	tmpFile, err := ioutil.TempFile(dir, "omw-merge*.toml")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating temporary file")
+	}
+	defer os.Remove(tmpFile.Name())
+	_, err = tmpFile.Write(dataBytes)
+	tmpFile.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "writing temporary file")
+	}
+	data, _, err = validateEdit(tmpFile.Name())
+	return data, err
+}
+
+// String describes a conflict for the omw merge-file output
+func (c MergeConflict) String() string {
+	return fmt.Sprintf("%s: kept %q ending %s from %s, dropped %q ending %s from %s",
+		c.ID, c.Kept.Task, c.Kept.End.Format("2006-01-02 15:04"), c.KeptFrom,
+		c.Dropped.Task, c.Dropped.End.Format("2006-01-02 15:04"), c.DroppedFrom)
+}
@@ -0,0 +1,78 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeTimesheets(t *testing.T) {
+	at := func(hour, min int) time.Time {
+		return time.Date(2020, 1, 2, hour, min, 0, 0, time.UTC)
+	}
+	older := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	a := timesheet{"a.toml", older, &SavedItems{Entries: []SavedEntry{
+		{ID: "1", End: at(9, 0), Task: "hello"},
+		{ID: "2", End: at(10, 0), Task: "review"},
+		{ID: "3", End: at(11, 0), Task: "standup"},
+	}}}
+	b := timesheet{"b.toml", newer, &SavedItems{Entries: []SavedEntry{
+		{ID: "4", End: at(9, 30), Task: "email"},
+		{ID: "2", End: at(10, 30), Task: "review"},
+		{ID: "3", End: at(11, 0), Task: "standup **"},
+		{ID: "1", End: at(9, 0), Task: "hello"},
+	}}}
+
+	data, result := mergeTimesheets([]timesheet{a, b})
+	want := []SavedEntry{
+		{ID: "1", End: at(9, 0), Task: "hello"},
+		{ID: "4", End: at(9, 30), Task: "email"},
+		{ID: "2", End: at(10, 30), Task: "review"},
+		{ID: "3", End: at(11, 0), Task: "standup **"},
+	}
+	if len(data.Entries) != len(want) {
+		t.Fatalf("mergeTimesheets() = %+v, want %+v", data.Entries, want)
+	}
+	for i := range want {
+		if data.Entries[i] != want[i] {
+			t.Errorf("mergeTimesheets() entry %d = %+v, want %+v", i, data.Entries[i], want[i])
+		}
+	}
+	if result.Entries != 4 || len(result.Conflicts) != 2 {
+		t.Fatalf("mergeTimesheets() result = %+v, want 4 entries and 2 conflicts", result)
+	}
+	for _, c := range result.Conflicts {
+		if c.KeptFrom != "b.toml" || c.DroppedFrom != "a.toml" {
+			t.Errorf("conflict %s kept %s over %s, want b.toml over a.toml", c.ID, c.KeptFrom, c.DroppedFrom)
+		}
+	}
+}
+
+func TestMergeTimesheets_modified(t *testing.T) {
+	at := func(hour, min int) time.Time {
+		return time.Date(2020, 1, 2, hour, min, 0, 0, time.UTC)
+	}
+	older := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	// the entry was edited back to an earlier end time in a.toml, after
+	// b.toml last saw it, but b.toml was written to later
+	edited := SavedEntry{ID: "1", End: at(9, 0), Task: "review", Modified: at(12, 0)}
+	stale := SavedEntry{ID: "1", End: at(10, 0), Task: "review", Modified: at(10, 0)}
+	legacy := SavedEntry{ID: "2", End: at(11, 0), Task: "standup"}
+	a := timesheet{"a.toml", older, &SavedItems{Entries: []SavedEntry{edited, {ID: "2", End: at(11, 30), Task: "standup"}}}}
+	b := timesheet{"b.toml", newer, &SavedItems{Entries: []SavedEntry{stale, legacy}}}
+
+	data, result := mergeTimesheets([]timesheet{a, b})
+	want := []SavedEntry{edited, legacy}
+	if len(data.Entries) != len(want) {
+		t.Fatalf("mergeTimesheets() = %+v, want %+v", data.Entries, want)
+	}
+	for i := range want {
+		if data.Entries[i] != want[i] {
+			t.Errorf("mergeTimesheets() entry %d = %+v, want %+v", i, data.Entries[i], want[i])
+		}
+	}
+	if len(result.Conflicts) != 2 || result.Conflicts[0].KeptFrom != "a.toml" || result.Conflicts[1].KeptFrom != "b.toml" {
+		t.Errorf("mergeTimesheets() conflicts = %+v, want 1 kept from a.toml and 2 from b.toml", result.Conflicts)
+	}
+}
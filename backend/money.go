@@ -0,0 +1,46 @@
+package backend
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Currency describes how money amounts are written in reports
+// The thousands separator is whichever of ',' and '.' isn't the decimal
+// separator, so "." gives $1,234.56 and "," gives 1.234,56 €.
+type Currency struct {
+	Symbol           string
+	Suffix           bool
+	DecimalSeparator string
+}
+
+// DefaultCurrency writes amounts with US conventions, such as $1,234.56
+var DefaultCurrency = Currency{Symbol: "$", DecimalSeparator: "."}
+
+// Format writes amount rounded to cents with the currency symbol and
+// separators
+func (c Currency) Format(amount float64) string {
+	decimal, thousands := ".", ","
+	if c.DecimalSeparator == "," {
+		decimal, thousands = ",", "."
+	}
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	cents := int64(math.Round(amount * 100))
+	units := fmt.Sprintf("%d", cents/100)
+	groups := []string{}
+	for len(units) > 3 {
+		groups = append([]string{units[len(units)-3:]}, groups...)
+		units = units[:len(units)-3]
+	}
+	groups = append([]string{units}, groups...)
+	number := fmt.Sprintf("%s%s%02d", strings.Join(groups, thousands), decimal, cents%100)
+	if c.Suffix {
+		return sign + number + " " + c.Symbol
+	}
+	return sign + c.Symbol + number
+}
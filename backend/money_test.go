@@ -0,0 +1,27 @@
+package backend
+
+import "testing"
+
+func TestCurrency_Format(t *testing.T) {
+	euro := Currency{Symbol: "€", Suffix: true, DecimalSeparator: ","}
+	tests := []struct {
+		name     string
+		currency Currency
+		amount   float64
+		want     string
+	}{
+		{"default", DefaultCurrency, 1234.56, "$1,234.56"},
+		{"small", DefaultCurrency, 5, "$5.00"},
+		{"millions", DefaultCurrency, 1234567.891, "$1,234,567.89"},
+		{"negative", DefaultCurrency, -1000, "-$1,000.00"},
+		{"suffix with comma decimals", euro, 1234.56, "1.234,56 €"},
+		{"prefix with comma decimals", Currency{Symbol: "€", DecimalSeparator: ","}, 1234.56, "€1.234,56"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.currency.Format(tt.amount); got != tt.want {
+				t.Errorf("Currency.Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
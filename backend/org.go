@@ -0,0 +1,96 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// orgTimestamp is the layout of an inactive Org-mode timestamp
+const orgTimestamp = "[2006-01-02 Mon 15:04]"
+
+// orgHeading collects the clock lines of one task on one day
+type orgHeading struct {
+	title  string
+	tags   []string
+	clocks []string
+}
+
+// formatOrg renders the tasks of a report as Org-mode headings with
+// CLOCK: lines, under a heading for each day
+// Tasks with the same title and project on a day share a heading.  The
+// project and #tags become Org tags.  Breaks, ignored time, unaccounted
+// gaps and zero-length entries are not clocked work, so they are left out.
+func formatOrg(report Report) (string, error) {
+	var sb strings.Builder
+	day := ""
+	headings := []*orgHeading{}
+	index := map[string]*orgHeading{}
+	flush := func() {
+		if day == "" {
+			return
+		}
+		fmt.Fprintf(&sb, "* %s\n", day)
+		for _, h := range headings {
+			sb.WriteString("** " + h.title)
+			if len(h.tags) > 0 {
+				sb.WriteString(" :" + strings.Join(h.tags, ":") + ":")
+			}
+			sb.WriteString("\n")
+			for _, clock := range h.clocks {
+				sb.WriteString("   " + clock + "\n")
+			}
+		}
+	}
+	for _, entry := range report.Entries {
+		if entry.Brk || entry.Ignore || entry.Unaccounted || entry.Duration == 0 {
+			continue
+		}
+		if d := entry.Start.Format("2006-01-02"); d != day {
+			flush()
+			day = d
+			headings = headings[:0]
+			index = map[string]*orgHeading{}
+		}
+		key := entry.Project + "\x00" + entry.Title
+		h, ok := index[key]
+		if !ok {
+			h = &orgHeading{title: entry.Title, tags: orgTags(entry)}
+			index[key] = h
+			headings = append(headings, h)
+		}
+		end := entry.Start.Add(entry.Duration)
+		h.clocks = append(h.clocks, fmt.Sprintf("CLOCK: %s--%s => %s",
+			entry.Start.Format(orgTimestamp), end.Format(orgTimestamp), orgDuration(entry.Duration)))
+	}
+	flush()
+	return sb.String(), nil
+}
+
+// orgTags turns the project and tags of entry into Org tags, which may
+// only contain letters, digits, '_', '@', '#' and '%'
+// Sub-projects such as acme/backend become acme_backend.
+func orgTags(entry ReportEntry) []string {
+	tags := []string{}
+	for _, tag := range append([]string{entry.Project}, entry.Tags...) {
+		if tag == "" {
+			continue
+		}
+		tags = append(tags, strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+				return r
+			case r == '_' || r == '@' || r == '#' || r == '%':
+				return r
+			}
+			return '_'
+		}, tag))
+	}
+	return tags
+}
+
+// orgDuration formats d as Org-mode does after a clock line, as H:MM
+func orgDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%2d:%02d", d/time.Hour, (d%time.Hour)/time.Minute)
+}
@@ -0,0 +1,48 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_formatOrg(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2020, 3, day, hour, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name    string
+		entries []ReportEntry
+		want    string
+	}{
+		{"empty", nil, ""},
+		{"shared heading", []ReportEntry{
+			{Start: at(2, 9), Duration: time.Hour, Title: "api", Project: "acme/backend", Tags: []string{"review"}},
+			{Start: at(2, 10), Duration: 30 * time.Minute, Title: "lunch", Brk: true},
+			{Start: at(2, 10), Duration: 30 * time.Minute, Title: "unaccounted", Unaccounted: true},
+			{Start: at(2, 11), Duration: 150 * time.Minute, Title: "api", Project: "acme/backend", Tags: []string{"review"}},
+		}, `* 2020-03-02
+** api :acme_backend:review:
+   CLOCK: [2020-03-02 Mon 09:00]--[2020-03-02 Mon 10:00] =>  1:00
+   CLOCK: [2020-03-02 Mon 11:00]--[2020-03-02 Mon 13:30] =>  2:30
+`},
+		{"one heading per day", []ReportEntry{
+			{Start: at(2, 9), Duration: time.Hour, Title: "docs"},
+			{Start: at(3, 9), Title: "hello"},
+			{Start: at(3, 9), Duration: 10 * time.Hour, Title: "docs"},
+		}, `* 2020-03-02
+** docs
+   CLOCK: [2020-03-02 Mon 09:00]--[2020-03-02 Mon 10:00] =>  1:00
+* 2020-03-03
+** docs
+   CLOCK: [2020-03-03 Tue 09:00]--[2020-03-03 Tue 19:00] => 10:00
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatOrg(Report{Entries: tt.entries})
+			if err != nil || got != tt.want {
+				t.Errorf("formatOrg() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package backend
+
+import "time"
+
+// overtime splits the task hours of each tracked day into regular hours,
+// up to expected, and overtime past it
+// As with runningBalance() nothing is expected on weekends, so all
+// weekend work counts as overtime.
+func overtime(entries []ReportEntry, expected time.Duration) ([]DayTotal, time.Duration) {
+	days := dayTotals(entries)
+	var total time.Duration
+	for i := range days {
+		day := &days[i]
+		if weekday := day.Date.Weekday(); weekday != time.Saturday && weekday != time.Sunday {
+			day.Expected = expected
+		}
+		day.Regular = day.TaskHrs
+		if day.TaskHrs > day.Expected {
+			day.Regular = day.Expected
+			day.Overtime = day.TaskHrs - day.Expected
+		}
+		total += day.Overtime
+	}
+	return days, total
+}
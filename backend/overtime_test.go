@@ -0,0 +1,41 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_overtime(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2020, 3, d, 17, 0, 0, 0, time.UTC)
+	}
+	// March 6 2020 is a Friday
+	entries := []ReportEntry{
+		{Title: "api", Duration: 10 * time.Hour, Ts: day(6)},
+		{Title: "lunch", Brk: true, Duration: time.Hour, Ts: day(6)},
+		{Title: "deploy", Duration: 3 * time.Hour, Ts: day(7)},
+		{Title: "api", Duration: 6 * time.Hour, Ts: day(9)},
+	}
+	want := []struct {
+		date     string
+		regular  time.Duration
+		overtime time.Duration
+	}{
+		{"2020-03-06", 8 * time.Hour, 2 * time.Hour},
+		{"2020-03-07", 0, 3 * time.Hour},
+		{"2020-03-09", 6 * time.Hour, 0},
+	}
+	days, total := overtime(entries, 8*time.Hour)
+	if len(days) != len(want) {
+		t.Fatalf("overtime() returned %d days, want %d", len(days), len(want))
+	}
+	for i, w := range want {
+		d := days[i]
+		if d.Date.Format("2006-01-02") != w.date || d.Regular != w.regular || d.Overtime != w.overtime {
+			t.Errorf("day %d = %s %s regular, %s overtime, want %s %s, %s", i, d.Date.Format("2006-01-02"), d.Regular, d.Overtime, w.date, w.regular, w.overtime)
+		}
+	}
+	if total != 5*time.Hour {
+		t.Errorf("overtime() total = %s, want 5h0m0s", total)
+	}
+}
@@ -0,0 +1,63 @@
+package backend
+
+import (
+	"bytes"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DayReport is the formatted report of a single day
+type DayReport struct {
+	Date   time.Time
+	Output string
+}
+
+// ReportPerDay runs Report() separately for each day from start to end,
+// so every day can be saved on its own
+// Days without entries are skipped.  The reports are meant for files, so
+// they are never styled.
+func (b *Backend) ReportPerDay(start, end string, format string, opts ReportOptions) ([]DayReport, error) {
+	if b.config.settings.Color {
+		b.config.settings.Color = false
+		defer func() { b.config.settings.Color = true }()
+	}
+	loc := time.Now().Location()
+	order := b.config.settings.DateOrder
+	from, err := parseDate(start, order, loc)
+	if err != nil {
+		return nil, withCode(CodeParse, errors.Wrap(err, "can't parse report start time"))
+	}
+	to, err := parseDate(end, order, loc)
+	if err != nil {
+		return nil, withCode(CodeParse, errors.Wrap(err, "can't parse report end time"))
+	}
+	if to.Before(from) {
+		return nil, withCode(CodeParse, errors.Errorf("report end %s is before report start %s", end, start))
+	}
+	// every day reads the whole input, so keep it for the next day
+	var input []byte
+	if opts.Input != nil {
+		input, err = ioutil.ReadAll(opts.Input)
+		if err != nil {
+			return nil, err
+		}
+	}
+	reports := []DayReport{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		if input != nil {
+			opts.Input = bytes.NewReader(input)
+		}
+		output, err := b.Report(date, date, format, opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "report for %s", date)
+		}
+		if b.lastReport == nil || len(b.lastReport.Entries) == 0 {
+			continue
+		}
+		reports = append(reports, DayReport{Date: day, Output: output})
+	}
+	return reports, nil
+}
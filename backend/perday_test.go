@@ -0,0 +1,42 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBackend_ReportPerDay(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T09:30:00Z
+  task = "coffee **"
+[[entries]]
+  id = "3"
+  end = 2020-03-04T10:00:00Z
+  task = "hello"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	settings := b.Settings()
+	settings.Color = true
+	b.Configure(settings)
+	reports, err := b.ReportPerDay("2020-03-02", "2020-03-04", "text", ReportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 || reports[0].Date.Day() != 2 || reports[1].Date.Day() != 4 {
+		t.Fatalf("Backend.ReportPerDay() = %d reports, want March 2 and 4", len(reports))
+	}
+	for _, r := range reports {
+		if strings.Contains(r.Output, "\x1b[") {
+			t.Errorf("Backend.ReportPerDay() for %s is styled: %q", r.Date.Format("2006-01-02"), r.Output)
+		}
+	}
+	if !b.Settings().Color {
+		t.Error("Backend.ReportPerDay() didn't restore the color setting")
+	}
+}
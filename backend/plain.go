@@ -0,0 +1,55 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// formatPlain renders a report as one line per entry and a line of
+// totals, without the day banners of the text templates
+// Zero-length entries such as the first entry of each day are left out.
+func formatPlain(report Report) string {
+	var sb strings.Builder
+	for _, entry := range report.Entries {
+		if entry.Duration == 0 {
+			continue
+		}
+		if entry.Rollup > 0 {
+			fmt.Fprintf(&sb, "%s %s: %d entries, %s total\n", entry.Ts.Format("2006-01-02"), entry.Title, entry.Rollup, shortDuration(entry.Duration))
+			continue
+		}
+		end := entry.Start.Add(entry.Duration)
+		fmt.Fprintf(&sb, "%s-%s %s %s", entry.Start.Format("2006-01-02 15:04"), end.Format("15:04"), shortDuration(entry.Duration), entry.Title)
+		if entry.Project != "" {
+			sb.WriteString(" @" + entry.Project)
+		}
+		if kind := entryType(entry); kind != "task" {
+			sb.WriteString(" [" + kind + "]")
+		}
+		if entry.Source != "" {
+			sb.WriteString(" <" + entry.Source + ">")
+		}
+		sb.WriteString("\n")
+	}
+	if report.TotalEntries > 0 {
+		fmt.Fprintf(&sb, "showing %d of %d entries\n", len(report.Entries), report.TotalEntries)
+	}
+	fmt.Fprintf(&sb, "task %s, billable %s, break %s, ignore %s\n",
+		shortDuration(report.TaskHrs), shortDuration(report.BillableHrs),
+		shortDuration(report.BrkHrs), shortDuration(report.IgnoreHrs))
+	return sb.String()
+}
+
+// shortDuration drops the zero minutes and seconds of d, so 1h15m0s
+// becomes 1h15m and 2h0m0s becomes 2h
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -0,0 +1,46 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_formatPlain(t *testing.T) {
+	start := time.Date(2020, 3, 2, 14, 30, 0, 0, time.UTC)
+	report := Report{
+		Entries: []ReportEntry{
+			{Start: start, Title: "hello"},
+			{Start: start, Duration: 75 * time.Minute, Title: "fix login", Project: "acme"},
+			{Start: start.Add(75 * time.Minute), Duration: 15 * time.Minute, Title: "coffee", Brk: true},
+		},
+		TaskHrs:     75 * time.Minute,
+		BillableHrs: time.Hour,
+		BrkHrs:      15 * time.Minute,
+	}
+	want := `2020-03-02 14:30-15:45 1h15m fix login @acme
+2020-03-02 15:45-16:00 15m coffee [break]
+task 1h15m, billable 1h, break 15m, ignore 0s
+`
+	if got := formatPlain(report); got != want {
+		t.Errorf("formatPlain() = %q, want %q", got, want)
+	}
+}
+
+func Test_shortDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{45 * time.Second, "45s"},
+		{15 * time.Minute, "15m"},
+		{2 * time.Hour, "2h"},
+		{75 * time.Minute, "1h15m"},
+		{time.Hour + 30*time.Second, "1h0m30s"},
+	}
+	for _, tt := range tests {
+		if got := shortDuration(tt.d); got != tt.want {
+			t.Errorf("shortDuration(%s) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// plainLogPath returns the plaintext log kept next to timesheet, such as
+// omw-plain.log for omw.toml
+// It isn't omw.log, the log of old versions that util/convert reads.
+func plainLogPath(timesheet string) string {
+	return strings.TrimSuffix(timesheet, filepath.Ext(timesheet)) + "-plain.log"
+}
+
+// appendPlainLog writes entries to the plaintext log, one line each in the
+// form "2024-01-02 14:30 — fix login @acme", if Settings.PlainLog is set
+// The log is only ever appended to and never read back, so editing the
+// timesheet doesn't change it.
+func (b *Backend) appendPlainLog(entries []SavedEntry) error {
+	if !b.config.settings.PlainLog {
+		return nil
+	}
+	var sb strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&sb, "%s — %s\n", e.End.Local().Format("2006-01-02 15:04"), e.Task)
+	}
+	fp, err := os.OpenFile(plainLogPath(b.config.omwFile), os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	_, err = fp.WriteString(sb.String())
+	if err != nil {
+		return err
+	}
+	return fp.Sync()
+}
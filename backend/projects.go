@@ -0,0 +1,53 @@
+package backend
+
+import "strings"
+
+// projectPaths returns project followed by each of its parent projects,
+// from the most to the least specific
+// Projects are nested with '/' in the @project token, so
+// acme/backend/api returns acme/backend/api, acme/backend, acme
+func projectPaths(project string) []string {
+	paths := []string{}
+	for project != "" {
+		paths = append(paths, project)
+		i := strings.LastIndex(project, "/")
+		if i < 0 {
+			break
+		}
+		project = project[:i]
+	}
+	return paths
+}
+
+// matchProject reports whether project is, or is a sub-project of, any
+// of the projects in list
+func matchProject(list []string, project string) bool {
+	for _, path := range projectPaths(project) {
+		if contains(list, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// projectTree rolls up the task hours of a report's entries into a tree
+// of projects, sorted by name at each level, where the hours of a project
+// include the hours of all of its sub-projects
+// It is --group-by project with one level of groups for each level of
+// sub-projects, so it shares GroupTotal and the "Group" template.
+func projectTree(entries []ReportEntry) []*GroupTotal {
+	root := &GroupTotal{}
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Brk || entry.Ignore || entry.Unaccounted || entry.Project == "" {
+			continue
+		}
+		node := root
+		for depth, name := range strings.Split(entry.Project, "/") {
+			node = node.subgroup("project", name, depth)
+			node.TaskHrs += entry.Duration
+		}
+	}
+	root.sort()
+	return root.Children
+}
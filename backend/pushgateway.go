@@ -0,0 +1,82 @@
+package backend
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// PushgatewayJob is the job label the report metrics are grouped under
+const PushgatewayJob = "omw"
+
+// pushMetrics are the report totals exported by FormatPushgateway
+var pushMetrics = []struct {
+	name  string
+	help  string
+	total func(Report) time.Duration
+}{
+	{"omw_task_seconds", "Time spent on tasks in the report range.", func(r Report) time.Duration { return r.TaskHrs }},
+	{"omw_billable_seconds", "Billable task time in the report range.", func(r Report) time.Duration { return r.BillableHrs }},
+	{"omw_break_seconds", "Time spent on breaks in the report range.", func(r Report) time.Duration { return r.BrkHrs }},
+	{"omw_ignore_seconds", "Ignored time in the report range.", func(r Report) time.Duration { return r.IgnoreHrs }},
+}
+
+// formatPushgateway renders the report totals in the Prometheus text
+// exposition format accepted by a Pushgateway, along with the task time
+// of each project
+func formatPushgateway(report Report) string {
+	var sb strings.Builder
+	for _, m := range pushMetrics {
+		fmt.Fprintf(&sb, "# HELP %s %s\n# TYPE %s gauge\n", m.name, m.help, m.name)
+		fmt.Fprintf(&sb, "%s %g\n", m.name, m.total(report).Seconds())
+	}
+	projects := map[string]time.Duration{}
+	for _, entry := range report.Entries {
+		if entry.Brk || entry.Ignore || entry.Unaccounted || entry.Project == "" {
+			continue
+		}
+		projects[entry.Project] += entry.Duration
+	}
+	names := []string{}
+	for name := range projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sb.WriteString("# HELP omw_project_task_seconds Time spent on the tasks of each project in the report range.\n")
+	sb.WriteString("# TYPE omw_project_task_seconds gauge\n")
+	for _, name := range names {
+		fmt.Fprintf(&sb, "omw_project_task_seconds{project=\"%s\"} %g\n", escapeLabel(name), projects[name].Seconds())
+	}
+	return sb.String()
+}
+
+// escapeLabel escapes a Prometheus label value
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// PushMetrics POSTs metrics from a FormatPushgateway report to the
+// configured Pushgateway, replacing the metrics of the same names
+// previously pushed for the omw job
+func (b *Backend) PushMetrics(metrics string) error {
+	gateway := b.config.settings.Pushgateway
+	if gateway == "" {
+		return errors.New("no pushgateway URL configured")
+	}
+	url := fmt.Sprintf("%s/metrics/job/%s", strings.TrimSuffix(gateway, "/"), PushgatewayJob)
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Post(url, "text/plain; version=0.0.4", bytes.NewReader([]byte(metrics)))
+	if err != nil {
+		return errors.Wrap(err, "can't push metrics")
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return errors.Errorf("can't push metrics: %s returned %s", url, resp.Status)
+	}
+	return nil
+}
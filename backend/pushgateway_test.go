@@ -0,0 +1,43 @@
+package backend
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackend_PushMetrics(t *testing.T) {
+	var path, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer srv.Close()
+
+	b, cleanup := newTestBackend(t, "")
+	defer cleanup()
+	b.config.settings.Pushgateway = srv.URL + "/"
+	b.Add([]string{"hello"})
+	b.Add([]string{"write docs @omw"})
+	today := time.Now().Format("2006-01-02")
+	metrics, err := b.Report(today, today, "prometheus-pushgateway", ReportOptions{})
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	if !strings.Contains(metrics, "omw_project_task_seconds{project=\"omw\"}") {
+		t.Errorf("Report() = %q, want the omw project task time", metrics)
+	}
+	if err := b.PushMetrics(metrics); err != nil {
+		t.Fatalf("PushMetrics() error = %v", err)
+	}
+	if path != "/metrics/job/omw" {
+		t.Errorf("PushMetrics() pushed to %s, want /metrics/job/omw", path)
+	}
+	if body != metrics {
+		t.Errorf("PushMetrics() pushed %q, want %q", body, metrics)
+	}
+}
@@ -0,0 +1,33 @@
+package backend
+
+import "time"
+
+// RawDurationsTemplateString defines the template used to output a
+// Report() with FormatText when raw durations are requested
+var RawDurationsTemplateString = `Raw Durations: {{.From.Format "2006-01-02"}} to {{(.To.AddDate 0 0 -1).Format "2006-01-02"}}
+{{range .Entries}}
+{{.Title}}{{if .ID}} [{{shortID .ID}}]{{end}}
+{{- with .Raw}}
+  previous: {{if .Previous.IsZero}}none, first entry of the report{{else}}{{.Previous.Format "2006-01-02T15:04:05.999999999Z07:00"}}{{end}}
+  end:      {{.End.Format "2006-01-02T15:04:05.999999999Z07:00"}}
+  duration: {{.Duration.Nanoseconds}}ns ({{.Duration}})
+{{- if .DayReset}}
+  day reset: the duration starts at end
+{{- end}}
+{{- if gt .Siblings 1}}
+  split between {{.Siblings}} entries with the same end
+{{- end}}
+{{- end}}
+{{end}}`
+
+// RawDuration records how the duration of a report entry was calculated
+// Duration is End minus Previous, unless DayReset is set because the
+// entry starts a new day or session, or the entry is one of Siblings
+// entries sharing End.
+type RawDuration struct {
+	Previous time.Time     `json:"previous"`
+	End      time.Time     `json:"end"`
+	Duration time.Duration `json:"duration"`
+	DayReset bool          `json:"dayReset"`
+	Siblings int           `json:"siblings,omitempty"`
+}
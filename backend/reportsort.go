@@ -0,0 +1,58 @@
+package backend
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SortKeys are the keys ReportOptions.Sort accepts, each optionally
+// prefixed with - for descending order
+var SortKeys = []string{"time", "duration", "title", "project"}
+
+// entryLess compares two report entries by each of the sort keys
+var entryLess = map[string]func(a, b *ReportEntry) bool{
+	"time":     func(a, b *ReportEntry) bool { return a.Ts.Before(b.Ts) },
+	"duration": func(a, b *ReportEntry) bool { return a.Duration < b.Duration },
+	"title":    func(a, b *ReportEntry) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) },
+	"project":  func(a, b *ReportEntry) bool { return a.Project < b.Project },
+}
+
+// sortReportEntries orders entries by key for output
+// Only time orders the whole report.  The other keys order the entries
+// within each day, so the entries of a day stay together under one day
+// header.  The sort is stable, so entries that compare equal stay in time
+// order.
+func sortReportEntries(entries []ReportEntry, key string) error {
+	if key == "" {
+		return nil
+	}
+	name := strings.TrimPrefix(key, "-")
+	less, ok := entryLess[name]
+	if !ok {
+		return withCode(CodeParse, errors.Errorf("can't sort by %q - expected one of %s, with - for descending", key, strings.Join(SortKeys, ", ")))
+	}
+	if strings.HasPrefix(key, "-") {
+		asc := less
+		less = func(a, b *ReportEntry) bool { return asc(b, a) }
+	}
+	if name == "time" {
+		sortEntriesBy(entries, less)
+		return nil
+	}
+	for start := 0; start < len(entries); {
+		end := start + 1
+		for end < len(entries) && sameDay(entries[end].Ts, entries[start].Ts) {
+			end++
+		}
+		sortEntriesBy(entries[start:end], less)
+		start = end
+	}
+	return nil
+}
+
+// sortEntriesBy stably sorts entries with less
+func sortEntriesBy(entries []ReportEntry, less func(a, b *ReportEntry) bool) {
+	sort.SliceStable(entries, func(i, j int) bool { return less(&entries[i], &entries[j]) })
+}
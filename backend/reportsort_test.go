@@ -0,0 +1,52 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_sortReportEntries(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2020, 3, 2+hour/24, hour%24, 0, 0, 0, time.UTC)
+	}
+	entries := func() []ReportEntry {
+		return []ReportEntry{
+			{ID: "1", Title: "standup", Project: "omw", Duration: 15 * time.Minute, Ts: at(9)},
+			{ID: "2", Title: "Review", Project: "acme", Duration: 2 * time.Hour, Ts: at(11)},
+			{ID: "3", Title: "api", Project: "acme", Duration: time.Hour, Ts: at(12)},
+			{ID: "4", Title: "a", Project: "acme", Duration: 30 * time.Minute, Ts: at(24 + 9)},
+			{ID: "5", Title: "zz", Project: "omw", Duration: 3 * time.Hour, Ts: at(24 + 10)},
+		}
+	}
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"", "12345"},
+		{"time", "12345"},
+		{"-time", "54321"},
+		{"duration", "13245"},
+		{"-duration", "23154"},
+		{"title", "32145"},
+		{"project", "23145"},
+		{"-project", "12354"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got := entries()
+			if err := sortReportEntries(got, tt.key); err != nil {
+				t.Fatal(err)
+			}
+			ids := ""
+			for _, e := range got {
+				ids += e.ID
+			}
+			if ids != tt.want {
+				t.Errorf("sortReportEntries(%q) order = %s, want %s", tt.key, ids, tt.want)
+			}
+		})
+	}
+	if err := sortReportEntries(entries(), "client"); Code(err) != CodeParse {
+		t.Errorf("sortReportEntries(%q) error = %v, want a %s error", "client", err, CodeParse)
+	}
+}
@@ -0,0 +1,41 @@
+package backend
+
+// rollupEntries collapses the break entries, the ignored entries, or both,
+// of each day into a single entry placed where the first of them was
+// Rollup counts the collapsed entries and Duration is their total, so
+// the report totals are unchanged.
+func rollupEntries(entries []ReportEntry, breaks, ignored bool) []ReportEntry {
+	out := []ReportEntry{}
+	rollups := map[string]int{}
+	for _, entry := range entries {
+		title := ""
+		switch {
+		case breaks && entry.Brk:
+			title = "Breaks"
+		case ignored && entry.Ignore:
+			title = "Ignored"
+		}
+		if title == "" || entry.Duration == 0 {
+			out = append(out, entry)
+			continue
+		}
+		key := entry.Ts.Format("2006-01-02") + title
+		if i, ok := rollups[key]; ok {
+			out[i].Rollup++
+			out[i].Duration += entry.Duration
+			continue
+		}
+		rollups[key] = len(out)
+		out = append(out, ReportEntry{
+			Brk:      entry.Brk,
+			Ignore:   entry.Ignore,
+			Title:    title,
+			Rollup:   1,
+			Duration: entry.Duration,
+			Start:    entry.Start,
+			End:      entry.End,
+			Ts:       entry.Ts,
+		})
+	}
+	return out
+}
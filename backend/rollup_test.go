@@ -0,0 +1,51 @@
+package backend
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func Test_rollupEntries(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2020, 3, day, hour, 0, 0, 0, time.UTC)
+	}
+	entries := []ReportEntry{
+		{Title: "hello", Ts: at(2, 9)},
+		{Title: "coffee", Brk: true, Duration: 10 * time.Minute, Ts: at(2, 10)},
+		{Title: "api", Duration: time.Hour, Ts: at(2, 11)},
+		{Title: "lunch", Brk: true, Duration: 30 * time.Minute, Ts: at(2, 12)},
+		{Title: "commute", Ignore: true, Duration: 20 * time.Minute, Ts: at(2, 13)},
+		{Title: "tea", Brk: true, Duration: 5 * time.Minute, Ts: at(2, 14)},
+		{Title: "coffee", Brk: true, Duration: 15 * time.Minute, Ts: at(3, 10)},
+	}
+	describe := func(entries []ReportEntry) []string {
+		lines := []string{}
+		for _, e := range entries {
+			if e.Rollup > 0 {
+				lines = append(lines, fmt.Sprintf("%s %d %s", e.Title, e.Rollup, e.Duration))
+			} else {
+				lines = append(lines, e.Title)
+			}
+		}
+		return lines
+	}
+	tests := []struct {
+		name    string
+		breaks  bool
+		ignored bool
+		want    []string
+	}{
+		{"breaks", true, false, []string{"hello", "Breaks 3 45m0s", "api", "commute", "Breaks 1 15m0s"}},
+		{"ignored", false, true, []string{"hello", "coffee", "api", "lunch", "Ignored 1 20m0s", "tea", "coffee"}},
+		{"both", true, true, []string{"hello", "Breaks 3 45m0s", "api", "Ignored 1 20m0s", "Breaks 1 15m0s"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := describe(rollupEntries(entries, tt.breaks, tt.ignored))
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("rollupEntries() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package backend
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,7 +14,9 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
@@ -31,31 +35,133 @@ const (
 	FormatJSON = iota
 	// FormatText indicates that user requested text template report format output
 	FormatText
+	// FormatClockify indicates that user requested Clockify CSV import format output
+	FormatClockify
+	// FormatXLSX indicates that user requested an Excel workbook
+	FormatXLSX
+	// FormatOrg indicates that user requested Org-mode clock entries
+	FormatOrg
+	// FormatSummary indicates that user requested only the total task hours
+	FormatSummary
+	// FormatPushgateway indicates that user requested Prometheus metrics
+	// for a Pushgateway
+	FormatPushgateway
+	// FormatKimai indicates that user requested Kimai CSV import format output
+	FormatKimai
+	// FormatCSV indicates that user requested one CSV row per entry
+	FormatCSV
+	// FormatMarkdown indicates that user requested Markdown tables
+	FormatMarkdown
+	// FormatICS indicates that user requested an iCalendar file
+	FormatICS
 )
 
 func (d formatType) String() string {
-	return [...]string{"FC", "JSON", "Text"}[d]
+	return [...]string{"FC", "JSON", "Text", "Clockify", "XLSX", "Org", "Summary", "Pushgateway", "Kimai", "CSV", "Markdown", "ICS"}[d]
 }
 
 // TemplateString defines the template used to output a Report() with FormatText
-var TemplateString = `{{define "Entry"}}
+var TemplateString = `{{define "Group"}}
+{{padRight 30 (print .Indent .Name)}} {{.TaskHrs}}
+{{- range .Children}}{{template "Group" .}}{{end}}
+{{- end}}
+{{- define "Entry"}}
+{{if or .Brk .Ignore}}{{style "dim"}}{{else if .Unaccounted}}{{style "yellow"}}{{end -}}
+{{if .Rollup -}}
+{{.Title}}: {{.Rollup}} entries, {{.Duration}} total
+{{- else -}}
 ({{- .Duration}}) {{.Start.Hour}}:{{.Start.Minute}}-{{.Ts.Hour}}:{{.Ts.Minute}} -- {{.Title -}}
+{{if .Project}} @{{.Project}}{{end -}}
+{{end -}}
+{{if or .Brk .Ignore .Unaccounted}}{{style "reset"}}{{end -}}
 {{end}}
 
 Report Start: {{.From}}
 Report End: {{.To}}
+{{- if .Timezone}}
+Times In: {{.Timezone}}
+{{- end}}
+{{- if .Options.Round}}
+Durations Rounded To: {{.Options.Round}}
+{{- end}}
+{{- if .TotalEntries}}
+Showing {{len .Entries}} of {{.TotalEntries}} entries
+{{- end}}
+{{- if not .Options.EntriesOnly}}
 Total Task Hours: {{.TaskHrs}}
+Total Billable Hours: {{.BillableHrs}}
 Total Break Hours: {{.BrkHrs}}
+{{- range $category, $hours := .BrkCategories}}
+  {{$category}}: {{$hours}}
+{{- end}}
+{{- if .Options.BreakPolicy}}
+  paid, included in task hours: {{.PaidBrkHrs}}
+{{- end}}
 Total Ignore Hours: {{.IgnoreHrs}}
+Switches: {{.Switches}}
+{{- if .Options.FillGaps}}
+Total Unaccounted Hours: {{.UnaccountedHrs}}
+{{- end}}
+{{- end}}
 {{$day := "" }}
 {{range .Entries}}
-{{- if ne $day .End.Weekday.String}}
-{{$day = .End.Weekday.String}}
+{{- if ne $day (weekday .End)}}
+{{$day = weekday .End}}
 
------------------------ {{$day}}, {{.End.Year}}-{{.End.Month}}-{{.End.Day}} -----------------------
+{{style "bold"}}----------------------- {{$day}}, {{.End.Year}}-{{month .End}}-{{.End.Day}} -----------------------{{style "reset"}}
 {{end -}}
 {{- template "Entry" .}}
+{{- if and $.Options.ShowIDs .ID}} [{{shortID .ID}}]{{end}}
+{{- if .Source}} <{{.Source}}>{{end}}
 {{- end -}}
+{{- if .Options.RunningBalance}}
+
+
+----------------------- Running Balance -----------------------
+{{range .Days -}}
+{{.Date.Format "2006-01-02"}} worked {{.TaskHrs}} expected {{.Expected}} balance {{.Balance}}
+{{end -}}
+Final Balance: {{.Balance}}
+{{- end}}
+{{- if .Options.IncludeEmptyDays}}
+
+
+----------------------- Days -----------------------
+{{range .AllDays -}}
+{{padRight 10 (weekday .Date)}} {{.Date.Format "2006-01-02"}} {{.TaskHrs}}
+{{end -}}
+{{- end}}
+{{- if .Options.AnnotateOvertime}}
+
+
+----------------------- Overtime -----------------------
+{{range .OvertimeDays -}}
+{{padRight 10 (weekday .Date)}} {{.Date.Format "2006-01-02"}} {{.TaskHrs}}{{if .Overtime}} ({{.Overtime}} OT){{end}}
+{{end -}}
+Total Overtime: {{.OvertimeHrs}}
+{{- end}}
+{{- if .Options.ProjectTree}}
+
+
+----------------------- Projects -----------------------
+{{- range .Projects}}{{template "Group" .}}{{end}}
+{{- end}}
+{{- if .Options.GroupBy}}
+
+
+----------------------- Groups -----------------------
+{{- range .Groups}}{{template "Group" .}}{{end}}
+{{- end}}
+{{- if .Options.Estimates}}
+
+
+----------------------- Estimates -----------------------
+{{range .Estimates -}}
+{{padRight 30 .Title}} estimate {{.Estimate}} actual {{.Actual}} variance {{.Variance}}{{if .Project}} @{{.Project}}{{end}}
+{{else -}}
+No estimated tasks found
+{{end -}}
+{{- end}}
 `
 
 // Backend represents the context and configuration of every instance of the omw command
@@ -66,23 +172,54 @@ type Backend struct {
 	config     *config
 	fp         *os.File
 	lastReport *Report
+	lastEdit   *EditSummary
 	worker     *worker
+	cache      entryCache
+	webhooks   sync.WaitGroup
 }
 
 // ReportEntry describes a single entry in the timesheet
 // Omw report and the REST API calculate some of the missing
 // from the data stored on disk.
 type ReportEntry struct {
-	ID         string        `json:"id,omitempty"`
-	Brk        bool          `json:"break,omitempty"`
-	ClassNames []string      `json:"classNames,omitempty"`
-	Duration   time.Duration `json:"duration,omitempty"`
-	Ignore     bool          `json:"ignore,omitempty"`
-	Start      time.Time     `json:"start,omitempty"`
-	End        time.Time     `json:"end,omitempty"`
-	Title      string        `json:"title,omitempty"`
-	Ts         time.Time     `json:"timestamp,omitempty"`
-	URL        string        `json:"url,omitempty"`
+	ID          string        `json:"id,omitempty"`
+	Billable    *bool         `json:"billable,omitempty"`
+	Brk         bool          `json:"break,omitempty"`
+	BrkCategory string        `json:"breakCategory,omitempty"`
+	ClassNames  []string      `json:"classNames,omitempty"`
+	Client      string        `json:"client,omitempty"`
+	Duration    time.Duration `json:"duration,omitempty"`
+	Ignore      bool          `json:"ignore,omitempty"`
+	Project     string        `json:"project,omitempty"`
+	Start       time.Time     `json:"start,omitempty"`
+	Unaccounted bool          `json:"unaccounted,omitempty"`
+	End         time.Time     `json:"end,omitempty"`
+	Estimate    time.Duration `json:"estimate,omitempty"`
+	Modified    *time.Time    `json:"modified,omitempty"`
+	OffHours    bool          `json:"offHours,omitempty"`
+	Paid        bool          `json:"paid,omitempty"`
+	Raw         *RawDuration  `json:"raw,omitempty"`
+	Source      string        `json:"source,omitempty"`
+	Rollup      int           `json:"rollup,omitempty"`
+	Tags        []string      `json:"tags,omitempty"`
+	Title       string        `json:"title,omitempty"`
+	Ts          time.Time     `json:"timestamp,omitempty"`
+	URL         string        `json:"url,omitempty"`
+}
+
+// DayTotal describes the hours tracked on a single calendar day of a report
+// Balance is the running total of worked minus expected hours up to and
+// including this day, and Regular and Overtime split the task hours at
+// the expected hours
+type DayTotal struct {
+	Date      time.Time     `json:"date"`
+	TaskHrs   time.Duration `json:"taskTotalHours"`
+	BrkHrs    time.Duration `json:"breakTotalHours"`
+	IgnoreHrs time.Duration `json:"ignoreTotalHours"`
+	Expected  time.Duration `json:"expectedHours"`
+	Balance   time.Duration `json:"runningBalance"`
+	Regular   time.Duration `json:"regularHrs,omitempty"`
+	Overtime  time.Duration `json:"overtimeHrs,omitempty"`
 }
 
 // SavedItems describes the structure of the entire TOML
@@ -95,10 +232,14 @@ type SavedItems struct {
 // for each entry.
 // Note that the stored data is minimized to make it
 // more suitable for human consumption
+// Modified records when omw last added or changed the entry and is
+// missing from entries saved before it was introduced
 type SavedEntry struct {
-	ID   string    `toml:"id"`
-	End  time.Time `toml:"end"`
-	Task string    `toml:"task"`
+	ID       string        `toml:"id" json:"id"`
+	End      time.Time     `toml:"end" json:"end"`
+	Task     string        `toml:"task" json:"task"`
+	Duration time.Duration `toml:"duration,omitempty" json:"duration,omitempty"`
+	Modified time.Time     `toml:"modified,omitempty" json:"modified,omitempty"`
 }
 
 // FCReport describes the format of a FullCalendar-compatible report
@@ -110,19 +251,304 @@ type FCReport struct {
 // previous is only used during report calculation to
 // populate ReportEntry.Duration
 type Report struct {
-	From      time.Time     `json:"reportFrom"`
-	To        time.Time     `json:"reportTo"`
-	IgnoreHrs time.Duration `json:"ignoreTotalHours"`
-	BrkHrs    time.Duration `json:"breakTotalHours"`
-	TaskHrs   time.Duration `json:"taskTotalHours"`
-	Entries   []ReportEntry `json:"entries"`
-	previous  *time.Time
+	From           time.Time                `json:"reportFrom"`
+	To             time.Time                `json:"reportTo"`
+	Message        string                   `json:"message,omitempty"`
+	TotalEntries   int                      `json:"totalEntries,omitempty"`
+	IgnoreHrs      time.Duration            `json:"ignoreTotalHours"`
+	BrkHrs         time.Duration            `json:"breakTotalHours"`
+	BrkCategories  map[string]time.Duration `json:"breakCategories,omitempty"`
+	PaidBrkHrs     time.Duration            `json:"paidBreakTotalHours,omitempty"`
+	TaskHrs        time.Duration            `json:"taskTotalHours"`
+	BillableHrs    time.Duration            `json:"billableTotalHours"`
+	UnaccountedHrs time.Duration            `json:"unaccountedTotalHours,omitempty"`
+	Timezone       string                   `json:"timezone,omitempty"`
+	Switches       int                      `json:"switches"`
+	DaySwitches    []DaySwitches            `json:"switchesPerDay,omitempty"`
+	Entries        []ReportEntry            `json:"entries"`
+	Days           []DayTotal               `json:"days,omitempty"`
+	AllDays        []DayTotal               `json:"allDays,omitempty"`
+	OvertimeDays   []DayTotal               `json:"overtimeDays,omitempty"`
+	OvertimeHrs    time.Duration            `json:"overtimeTotalHours,omitempty"`
+	Balance        time.Duration            `json:"balance,omitempty"`
+	Projects       []*GroupTotal            `json:"projects,omitempty"`
+	Groups         []*GroupTotal            `json:"groups,omitempty"`
+	Calendar       *Calendar                `json:"calendar,omitempty"`
+	Accounting     *Accounting              `json:"accounting,omitempty"`
+	Invoice        *Invoice                 `json:"invoice,omitempty"`
+	Suspects       []Suspect                `json:"suspects,omitempty"`
+	Estimates      []EstimateTotal          `json:"estimates,omitempty"`
+	Distribution   []time.Duration          `json:"distribution,omitempty"`
+	Options        ReportOptions            `json:"-"`
+	previous       *time.Time
+	considered     int
+	filtered       int
+}
+
+// ReportOptions holds the optional report behaviors requested by the caller
+// The zero value produces the default report
+type ReportOptions struct {
+	// RunningBalance adds a per-day flex time balance of worked minus
+	// expected hours to the report
+	RunningBalance bool
+	// IncludeEmptyDays lists the totals of every day of the report
+	// period, including days with nothing tracked
+	IncludeEmptyDays bool
+	// AnnotateOvertime splits each day's task hours into regular hours
+	// and overtime past Settings.ExpectedHours
+	AnnotateOvertime bool
+	// AccuracyCheck lists suspiciously short or long entries instead of
+	// the usual report
+	AccuracyCheck bool
+	// RawDurations records how each entry's duration was calculated and
+	// lists that instead of the usual report, for debugging
+	RawDurations bool
+	// Distribution totals task time by hour of day instead of the usual
+	// report
+	Distribution bool
+	// Calendar shows the task hours of each day as a month calendar
+	// instead of the usual report
+	Calendar bool
+	// AccountFor checks that the task and break hours of each day add up
+	// to this, within AccountTolerance, instead of the usual report
+	AccountFor       time.Duration
+	AccountTolerance time.Duration
+	// FillGaps adds unaccounted entries for the parts of each weekday's
+	// working window that no entry covers
+	FillGaps bool
+	// ProjectTree adds task hours rolled up through the project hierarchy
+	ProjectTree bool
+	// GroupBy adds task hours grouped by each of these GroupKeys in turn,
+	// with subtotals at every level
+	GroupBy []string
+	// Estimates adds estimated versus actual hours for each project and
+	// title with an est: token
+	Estimates bool
+	// Invoice renders the report as an invoice to this client, limited
+	// to the client's billable tasks
+	Invoice string
+	// Clients limits the report to tasks for any of these clients
+	Clients []string
+	// Projects limits the report to tasks in any of these projects or
+	// their sub-projects
+	Projects []string
+	// ExcludeProjects drops tasks in any of these projects or their
+	// sub-projects
+	ExcludeProjects []string
+	// Tags limits the report to tasks tagged with any of these tags
+	Tags []string
+	// ExcludeTags drops tasks tagged with any of these tags
+	ExcludeTags []string
+	// ExcludeIDs drops the entries with these IDs, given in full or in
+	// the short form shown by ShowIDs
+	ExcludeIDs []string
+	// MinDuration drops entries shorter than this, such as accidental
+	// double adds
+	MinDuration time.Duration
+	// DisplayZone converts the entry times shown in the report to this
+	// timezone, after they have been grouped into days as stored
+	DisplayZone *time.Location
+	// Round rounds the duration of each entry to the nearest multiple of
+	// this, half up, before it is added to the totals
+	Round time.Duration
+	// BreakPolicy counts breaks shorter than Settings.PaidBreakLimit as
+	// paid, adding them to the task hours as well as the break hours
+	BreakPolicy bool
+	// ExcludeWeekendBreaks leaves breaks on weekends or outside the
+	// working window out of the break totals, while still listing them
+	ExcludeWeekendBreaks bool
+	// Limit keeps only the first Limit entries for output, or the last
+	// with Tail, while the totals still cover every entry
+	Limit int
+	Tail  bool
+	// Sort orders the entries for output by one of SortKeys, prefixed
+	// with - for descending order, instead of by time
+	// Keys other than time only reorder the entries within each day.
+	Sort string
+	// Email wraps text output with a subject, greeting and sign-off so
+	// it can be pasted into an email
+	Email bool
+	// Style names the template used for text output, either a built-in
+	// style or one configured in Settings.Styles
+	Style string
+	// ShowIDs adds the short form of each entry's ID to text output
+	ShowIDs bool
+	// AggregateBreaks and AggregateIgnored collapse the break or ignored
+	// entries of each day into one line of text output
+	AggregateBreaks  bool
+	AggregateIgnored bool
+	// Plain renders text output as one terse line per entry instead of
+	// through a template
+	Plain bool
+	// EntriesOnly skips the report totals, so entries that would be
+	// rejected while summing them are still listed, and JSON output is
+	// just the array of entries
+	EntriesOnly bool
+	// NoHook skips Settings.ReportHook for this report
+	NoHook bool
+	// ModifiedSince limits the report to entries added or changed after
+	// this time, for incremental exports
+	ModifiedSince time.Time
+	// Input is read for the timesheet instead of the configured file,
+	// such as an archive piped to stdin, and InputName names it
+	Input     io.Reader
+	InputName string
+	// ShowSource records the timesheet each entry was read from
+	ShowSource bool
+}
+
+// listsDays reports whether the report shows every day of its period,
+// which is still worth showing when nothing was tracked
+func (o ReportOptions) listsDays() bool {
+	return o.IncludeEmptyDays || o.RunningBalance || o.Calendar || o.AccountFor > 0
+}
+
+// includes reports whether entry passes the report filters
+// Inclusions are applied first and exclusions after, so a report can be
+// limited to a project while still dropping some of its tags
+func (o ReportOptions) includes(entry *ReportEntry) bool {
+	if len(o.Clients) > 0 && !contains(o.Clients, entry.Client) {
+		return false
+	}
+	if len(o.Projects) > 0 && !matchProject(o.Projects, entry.Project) {
+		return false
+	}
+	if len(o.Tags) > 0 && !hasTag(o.Tags, entry.Tags) {
+		return false
+	}
+	if matchProject(o.ExcludeProjects, entry.Project) {
+		return false
+	}
+	for _, tag := range entry.Tags {
+		if contains(o.ExcludeTags, tag) {
+			return false
+		}
+	}
+	if contains(o.ExcludeIDs, entry.ID) || contains(o.ExcludeIDs, shortID(entry.ID)) {
+		return false
+	}
+	if entry.Duration < o.MinDuration {
+		return false
+	}
+	if !o.ModifiedSince.IsZero() && (entry.Modified == nil || !entry.Modified.After(o.ModifiedSince)) {
+		return false
+	}
+	return true
+}
+
+// FilteredPercent returns the share of the entries in the report period
+// that the report filters left out
+func (r Report) FilteredPercent() int {
+	if r.considered == 0 {
+		return 0
+	}
+	return r.filtered * 100 / r.considered
+}
+
+// hasTag reports whether any of tags is one of want
+func hasTag(want, tags []string) bool {
+	for _, tag := range tags {
+		if contains(want, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether s is one of the values in list
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Settings holds the user preferences loaded from the omw config file
+type Settings struct {
+	// ExpectedHours is the length of a normal working day
+	ExpectedHours time.Duration
+	// FilterWarning is the percentage of entries that report filters
+	// may leave out before the report warns about it
+	FilterWarning int
+	// MinDuration and MaxDuration bound the entry durations that
+	// ReportOptions.AccuracyCheck considers plausible
+	MinDuration time.Duration
+	MaxDuration time.Duration
+	// WeekStart is the first day of the week
+	WeekStart time.Weekday
+	// FiscalYearStart is the first month of the fiscal year
+	FiscalYearStart time.Month
+	// PaidBreakLimit is the length below which breaks are paid under
+	// ReportOptions.BreakPolicy
+	PaidBreakLimit time.Duration
+	// DayStart and DayEnd are the offsets from midnight of the working
+	// window that ReportOptions.FillGaps accounts for and that
+	// ReportOptions.ExcludeWeekendBreaks checks breaks against
+	DayStart time.Duration
+	DayEnd   time.Duration
+	// NewestFirst keeps the timesheet on disk sorted with the newest entry
+	// at the top instead of appending new entries to the end
+	NewestFirst bool
+	// HelloStartsDay makes a "hello" entry, rather than midnight, restart
+	// the duration calculation in reports
+	HelloStartsDay bool
+	// Webhooks are URLs that every new entry is POSTed to as JSON
+	Webhooks []string
+	// PlainLog also appends every new entry to a plaintext .log file
+	// next to the timesheet, for reading and grepping
+	PlainLog bool
+	// Pushgateway is the URL of the Prometheus Pushgateway that
+	// prometheus-pushgateway reports are pushed to
+	Pushgateway string
+	// ReportHook is an executable that reports are piped to as JSON
+	// on stdin, its stdout replacing the formatted report
+	ReportHook string
+	// Currency formats the money amounts of invoices
+	Currency Currency
+	// Styles maps lowercase report style names to template files
+	Styles map[string]string
+	// TitleMaxLength truncates the task titles shown in text and Markdown
+	// reports to this many columns, 0 for no limit
+	TitleMaxLength int
+	// Locale is the language of the weekday and month names in text
+	// report day headers, as returned by ParseLocale()
+	Locale string
+	// DateOrder is DateOrderMDY or DateOrderDMY and decides whether report
+	// dates such as 01/02/2006 are read month or day first
+	DateOrder string
+	// SplitSeparator divides a task given to Add() into several entries
+	// with the same timestamp, empty to disable
+	SplitSeparator string
+	// TaskPrefix and TaskSuffix are added to every task given to Add()
+	// that doesn't already contain them
+	TaskPrefix string
+	TaskSuffix string
+	// Color enables ANSI styling in text reports
+	Color bool
+	// StoreUTC saves new entries with UTC timestamps instead of local time
+	StoreUTC bool
+	// Billable is the default billable status of a task
+	Billable bool
+	// BillableProjects overrides Billable for the tasks of a project
+	// Its keys are lowercase, as the config file's keys are read, and
+	// projects match them regardless of case.
+	BillableProjects map[string]bool
+	// Clients holds the invoice details of each !client, keyed by the
+	// lowercase name like BillableProjects
+	Clients map[string]Client
+	// Consultant holds your own invoice details and default rate
+	Consultant Party
+	// Email holds the headers and sign-off of ReportOptions.Email
+	Email Email
 }
 
 type config struct {
-	omwDir  string
-	omwFile string
-	omwTerm string
+	omwDir   string
+	omwFile  string
+	omwTerm  string
+	settings Settings
 }
 
 type worker struct {
@@ -131,20 +557,76 @@ type worker struct {
 	rightShiftDown bool
 }
 
-// Add appends the current time and task to your timesheet
-func (b *Backend) Add(args []string) error {
-	task := strings.Join(args, " ")
-	return b.addEntry(task)
+// Add appends the current time and task to your timesheet and returns
+// the saved entries
+// A task containing the split separator is saved as one entry for each
+// part, all with the same timestamp, and reports divide the time between
+// them.
+func (b *Backend) Add(args []string) ([]SavedEntry, error) {
+	tasks := splitTask(strings.Join(args, " "), b.config.settings.SplitSeparator)
+	if len(tasks) == 0 {
+		return nil, withCode(CodeParse, errors.New("missing task"))
+	}
+	for i := range tasks {
+		tasks[i] = b.decorateTask(normalizeTask(tasks[i]))
+		if err := validateEstimates(tasks[i]); err != nil {
+			return nil, err
+		}
+		if entry, err := b.parseEntry(tasks[i]); err != nil || entry.Title == "" {
+			return nil, withCode(CodeParse, errors.Errorf("task %q has no title - describe the task before any modifiers", tasks[i]))
+		}
+	}
+	return b.addEntries(tasks)
+}
+
+// normalizeTask collapses tabs, newlines and runs of spaces in task to
+// single spaces, which is how parseEntry() reads them anyway, so the
+// timesheet, plaintext log and reports all show the same text
+func normalizeTask(task string) string {
+	return strings.Join(strings.Fields(task), " ")
+}
+
+// decorateTask adds the configured prefix and suffix to task unless it
+// already contains them.  The suffix goes before a trailing break or
+// ignore marker so that the marker is still recognized.
+func (b *Backend) decorateTask(task string) string {
+	prefix, suffix := b.config.settings.TaskPrefix, b.config.settings.TaskSuffix
+	if prefix != "" && !strings.Contains(task, prefix) {
+		task = prefix + " " + task
+	}
+	if suffix != "" && !strings.Contains(task, suffix) {
+		words := strings.Fields(task)
+		marker := ""
+		if n := len(words); n > 0 && (words[n-1] == "**" || words[n-1] == "***") {
+			last := words[n-1]
+			marker = " " + last
+			words = words[:len(words)-1]
+		}
+		task = strings.Join(append(words, suffix), " ") + marker
+	}
+	return task
 }
 
-// Close cleans up before exiting
+// Close waits briefly for webhooks still being sent and cleans up before
+// exiting
 func (b *Backend) Close() error {
+	b.waitWebhooks(webhookWait)
 	if b.fp != nil {
 		b.fp.Close()
 	}
 	return nil
 }
 
+// Configure applies the user's settings to the backend
+func (b *Backend) Configure(s Settings) {
+	b.config.settings = s
+}
+
+// Settings returns the settings the backend was configured with
+func (b *Backend) Settings() Settings {
+	return b.config.settings
+}
+
 // Edit opens your current timesheet in your default editor or
 // in the editor specified by the EDITOR environment variable
 // Similar to visudo, will do some basic checks to ensure
@@ -162,7 +644,7 @@ func (b *Backend) Edit() (bool, error) {
 		return false, err
 	}
 	if !locked {
-		return false, errors.New("unable to get file lock")
+		return false, withCode(CodeLock, errors.New("unable to get file lock"))
 	}
 
 	// copy file
@@ -171,16 +653,30 @@ func (b *Backend) Edit() (bool, error) {
 		return false, err
 	}
 	defer source.Close()
+	original, err := readTimesheet(b.config.omwFile)
+	if err != nil {
+		return false, err
+	}
 	pat := fmt.Sprintf("%s*", filepath.Base(b.config.omwFile))
 	tmpFile, err := ioutil.TempFile(filepath.Dir(b.config.omwFile), pat)
-	defer tmpFile.Close()
 	if err != nil {
 		return false, err
 	}
-	_, err = io.Copy(tmpFile, source)
+	tmpPath := tmpFile.Name()
+	// the temporary file must never outlive Edit() - on success it has
+	// been renamed over the timesheet and removing it is a no-op
+	defer func() {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+	}()
+	// remember what was copied, so a change made to the timesheet while
+	// the editor is open is not silently overwritten
+	sum := sha256.New()
+	_, err = io.Copy(tmpFile, io.TeeReader(source, sum))
 	if err != nil {
 		return false, err
 	}
+	copied := sum.Sum(nil)
 
 	if preferredEditor := os.Getenv("EDITOR"); preferredEditor != "" {
 		editor = preferredEditor
@@ -191,7 +687,6 @@ func (b *Backend) Edit() (bool, error) {
 		runCmd = fmt.Sprintf("%s -e %s", term, editor)
 	}
 
-	tmpPath := tmpFile.Name()
 	argv := []string{tmpPath}
 	cmd := exec.CommandContext(b.ctx, runCmd, argv...)
 	// should work if run from terminal
@@ -199,9 +694,7 @@ func (b *Backend) Edit() (bool, error) {
 	cmd.Stdout = os.Stdout
 	err = runCommand(cmd)
 	if err != nil {
-		tmpFile.Close()
-		inner := os.Remove(tmpPath)
-		return false, errors.Wrap(err, inner.Error())
+		return false, editorError(runCmd, err)
 	}
 
 	// after edits, lock tmpFile and validate changes
@@ -209,29 +702,22 @@ func (b *Backend) Edit() (bool, error) {
 	tmpLocked, err := tmpLock.TryLock()
 	defer tmpLock.Unlock()
 	if err != nil {
-		tmpFile.Close()
-		inner := os.Remove(tmpPath)
-		return false, errors.Wrap(err, inner.Error())
+		return false, err
 	}
 	if !tmpLocked {
-		tmpFile.Close()
-		err = errors.New("unable to get file lock on tmpFile")
-		inner := os.Remove(tmpPath)
-		return false, errors.Wrap(err, inner.Error())
+		return false, withCode(CodeLock, errors.New("unable to get file lock on tmpFile"))
 	}
 
-	validated, err := validateEdit(tmpFile.Name())
+	validated, fixed, err := validateEdit(tmpPath)
 	if err != nil {
-		tmpFile.Close()
-		inner := os.Remove(tmpPath)
-		innerErr := ""
-		if inner != nil {
-			innerErr = inner.Error()
-		}
-		return true, errors.Wrap(err, innerErr)
+		return true, err
 	}
 	if len(validated.Entries) == 0 {
-		return false, errors.Wrapf(err, "got zero entries from edit - manually remove %s to clear all tasks", b.config.omwFile)
+		return false, errors.Errorf("got zero entries from edit - manually remove %s to clear all tasks", b.config.omwFile)
+	}
+	changed := markModified(original.Entries, validated.Entries, b.now())
+	if b.config.settings.NewestFirst {
+		sortEntries(validated.Entries, true)
 	}
 	validatedBytes, err := toml.Marshal(validated)
 	if err != nil {
@@ -243,24 +729,133 @@ func (b *Backend) Edit() (bool, error) {
 	if err != nil {
 		return false, errors.Wrap(err, "reading backup file")
 	}
+	if current := sha256.Sum256(input); !bytes.Equal(current[:], copied) {
+		return false, b.keepEdit(validatedBytes)
+	}
 	backup := fmt.Sprintf("%s.bak", b.config.omwFile)
-	err = ioutil.WriteFile(backup, input, 0644)
+	err = writeFileSync(backup, input, 0644)
 	if err != nil {
 		return false, errors.Wrap(err, "writing backup file")
 	}
 
-	err = ioutil.WriteFile(tmpFile.Name(), validatedBytes, 0644)
+	err = writeFileSync(tmpPath, validatedBytes, 0644)
 	if err != nil {
 		return false, errors.Wrap(err, "saving new data")
 	}
-	os.Rename(tmpPath, b.config.omwFile)
-	return false, err
+	tmpFile.Close()
+	err = renameSync(tmpPath, b.config.omwFile)
+	b.cache.invalidate()
+	if err != nil {
+		return false, errors.Wrap(err, "replacing data file")
+	}
+	b.refreshStatus(validated.Entries)
+	b.lastEdit = &EditSummary{
+		Before:   len(original.Entries),
+		After:    len(validated.Entries),
+		Changed:  changed,
+		FixedIDs: fixed,
+		Backup:   backup,
+	}
+	return false, nil
+}
+
+// EditSummary describes the changes saved by Edit()
+type EditSummary struct {
+	// Before and After are the number of entries in the timesheet
+	Before int
+	After  int
+	// Changed counts the entries added or modified
+	Changed int
+	// FixedIDs counts the duplicate IDs that were replaced
+	FixedIDs int
+	// Backup is the copy of the timesheet from before the edit
+	Backup string
+}
+
+// LastEdit returns the summary of the most recent Edit() that saved the
+// timesheet, or nil if nothing has been saved
+func (b *Backend) LastEdit() *EditSummary {
+	return b.lastEdit
+}
+
+// keepEdit saves the edited timesheet next to the original when the
+// original changed while it was being edited, so neither version is lost
+func (b *Backend) keepEdit(data []byte) error {
+	kept := fmt.Sprintf("%s.edit-%s", b.config.omwFile, time.Now().Format("20060102-150405"))
+	err := writeFileSync(kept, data, 0644)
+	if err != nil {
+		return errors.Wrap(err, "file changed during edit, and saving the edited copy failed")
+	}
+	return withCode(CodeLock, errors.Errorf("file changed during edit - your changes were saved to %s, merge them with omw merge-file %s", kept, kept))
+}
+
+// editorError explains why the editor could not be used
+func editorError(editor string, err error) error {
+	if execErr, ok := err.(*exec.Error); ok {
+		return errors.Wrapf(execErr.Err, "editor %q not found - set EDITOR to an installed editor", editor)
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return errors.Errorf("editor %q exited with status %d - timesheet unchanged", editor, exitErr.ExitCode())
+	}
+	return errors.Wrapf(err, "running editor %q", editor)
 }
 
 // Hello appends a newline and then another line to end of timesheet with current time
 // and the word "Hello".  Meant to be run at the beginning of a new work day
 func (b *Backend) Hello() error {
-	return b.addEntry("hello")
+	_, err := b.addEntry("hello")
+	return err
+}
+
+// LastReport returns the report calculated by the most recent call to
+// Report(), or nil if no report has been run
+func (b *Backend) LastReport() *Report {
+	return b.lastReport
+}
+
+// Reid gives every entry in the timesheet a new random ID, for example
+// to reset a copied file before merging it with the original.  Times and
+// tasks are left as they are.
+// Returns the number of entries whose ID changed.
+func (b *Backend) Reid() (int, error) {
+	changed := 0
+	err := b.updateEntries(func(data *SavedItems) (bool, error) {
+		now := b.now()
+		for i := range data.Entries {
+			data.Entries[i].ID = uuid.New().String()
+			data.Entries[i].Modified = now
+			changed++
+		}
+		return changed > 0, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
+
+// MigrateTZ rewrites the timestamp of every entry in the timesheet in the
+// timezone loc.  Only the representation changes - every entry still refers
+// to the same instant, so reports are unaffected.
+// Returns the number of entries that were converted.
+func (b *Backend) MigrateTZ(loc *time.Location) (int, error) {
+	converted := 0
+	err := b.updateEntries(func(data *SavedItems) (bool, error) {
+		now := b.now()
+		for i, e := range data.Entries {
+			end := e.End.In(loc)
+			if end.Format(time.RFC3339Nano) != e.End.Format(time.RFC3339Nano) {
+				converted++
+				data.Entries[i].Modified = now
+			}
+			data.Entries[i].End = end
+		}
+		return converted > 0, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return converted, nil
 }
 
 // Report outputs various report formats to one of the following types:
@@ -271,88 +866,234 @@ func (b *Backend) Hello() error {
 // --from 2019-01-01 --to 2019-01-02
 // that translates to "report on tasks that occurred between 2019-01-01 00:00
 // and "2019-01-03 00:00"
-func (b *Backend) Report(start, end string, format string) (output string, err error) {
-	fcLayout := "2006-01-02T15:04:05-07:00"
-	layout := "2006-1-2" // should support optional leading zeros
-	//layoutEvent := "2006-1-2 15:4"
-	report := Report{}
-	loc := time.Now().Location()
-	report.From, err = time.ParseInLocation(layout, start, loc)
-	if err != nil {
-		report.From, err = time.ParseInLocation(fcLayout, start, loc)
+func (b *Backend) Report(start, end string, format string, opts ReportOptions) (output string, err error) {
+	if opts.Invoice != "" {
+		opts.Clients = []string{opts.Invoice}
 	}
+	err = validGroupBy(opts.GroupBy)
 	if err != nil {
-		return "", errors.Wrap(err, "can't parse report start time")
+		return "", err
 	}
-
-	report.To, err = time.ParseInLocation(layout, end, loc)
+	if opts.Round < 0 {
+		return "", withCode(CodeParse, errors.Errorf("invalid rounding %s: must be positive", opts.Round))
+	}
+	report := Report{Options: opts}
+	loc := time.Now().Location()
+	order := b.config.settings.DateOrder
+	report.From, err = parseDate(start, order, loc)
 	if err != nil {
-		report.To, err = time.ParseInLocation(fcLayout, end, loc)
+		return "", withCode(CodeParse, errors.Wrap(err, "can't parse report start time"))
 	}
+
+	report.To, err = parseDate(end, order, loc)
 	if err != nil {
-		return "", errors.Wrap(err, "can't parse report end time")
+		return "", withCode(CodeParse, errors.Wrap(err, "can't parse report end time"))
 	}
 	report.To = report.To.Add(24 * time.Hour)
-	r, err := ioutil.ReadFile(b.config.omwFile)
+	if !report.To.After(report.From) {
+		return "", withCode(CodeParse, errors.Errorf("report end %s is before report start %s", end, start))
+	}
+	var data *SavedItems
+	source := b.config.omwFile
+	if opts.Input != nil {
+		data, err = parseTimesheet(opts.Input)
+		source = opts.InputName
+	} else {
+		data, err = b.readEntries()
+	}
 	if err != nil {
 		return "", errors.Wrap(err, "can't read data file for report")
 	}
-	data := SavedItems{}
-	err = toml.Unmarshal(r, &data)
-	if err != nil {
-		return "", errors.Wrap(err, "can't unmarshal data")
+	// durations are calculated between consecutive entries, so they
+	// must be in order whichever way the file is sorted
+	if !sort.SliceIsSorted(data.Entries, func(i, j int) bool { return data.Entries[i].End.Before(data.Entries[j].End) }) {
+		sortEntries(data.Entries, false)
+	}
+	// Only the entries in the requested time period are parsed, so a
+	// report on a recent day doesn't pay for the whole history
+	entries := periodEntries(data.Entries, report.From, report.To)
+	// With HelloStartsDay a task running past midnight into the period
+	// started at the entry before it, unless the period opens with hello
+	if b.config.settings.HelloStartsDay {
+		if prev := lastBefore(data.Entries, report.From); prev != nil {
+			ts := prev.End.In(loc)
+			report.previous = &ts
+		}
 	}
 
-	for _, e := range data.Entries {
+	stamps := []time.Time{}
+	siblings := countSiblings(entries)
+	group := siblingGroup{}
+	for _, e := range entries {
 		// Indicates line is missing required information
 		if e.Task == "" {
 			continue
 		}
-
-		// Indicates task timestamp is outside the requested time period
-		if e.End.Before(report.From) || e.End.After(report.To) {
-			continue
-		}
 		entry, err := b.parseEntry(e.Task)
 		if err != nil {
 			continue
 		}
-		entry.Ts = e.End
+		// Entries may be stored in UTC or any other zone, so always
+		// group and display them in the local timezone
+		entry.ID = e.ID
+		entry.Ts = e.End.In(loc)
+		if opts.ShowSource {
+			entry.Source = source
+		}
+		if !e.Modified.IsZero() {
+			modified := e.Modified
+			entry.Modified = &modified
+		}
+		stamps = append(stamps, entry.Ts)
 		if err != nil {
 			continue
 		}
 		// Should indicate first task in requested report time period
-		if report.previous == nil {
+		if report.previous == nil && e.Duration == 0 {
 			report.previous = &entry.Ts
 			entry.End = entry.Ts
-			report.Entries = append(report.Entries, *entry)
+			entry.Start = entry.Ts
+			if opts.RawDurations {
+				entry.Raw = &RawDuration{End: entry.Ts, DayReset: true}
+			}
+			if opts.includes(entry) {
+				report.Entries = append(report.Entries, *entry)
+			}
 			continue
 		}
-		// For now, we explicitly assume that a new day restarts the duration calculation
-		// We may change the marker from new day to first entry of "hello" on a given day
-		// to better allow tracking tasks that extend from a previous day into a new day
-		if entry.Ts.Day() != (*report.previous).Day() {
+		if report.previous == nil {
 			report.previous = &entry.Ts
-			entry.End = entry.Ts
 		}
-		entry.End = *report.previous
-		entry.Duration = entry.Ts.Sub(*report.previous)
+		// By default a new day restarts the duration calculation.  With
+		// HelloStartsDay only a "hello" entry does, so tasks that extend
+		// from a previous day into a new day keep their full duration.
+		newSession := !sameDay(entry.Ts, *report.previous)
+		if b.config.settings.HelloStartsDay {
+			newSession = isHello(entry)
+		}
+		previous := *report.previous
+		if newSession {
+			report.previous = &entry.Ts
+		}
+		// Entries added together with the split separator share a
+		// timestamp and divide the time since the previous entry
+		if !entry.Ts.Equal(group.ts) {
+			group = siblingGroup{ts: entry.Ts, start: *report.previous, n: siblings[e.End.UnixNano()]}
+		}
+		entry.End = group.start
+		entry.Start = group.start
+		if e.Duration > 0 {
+			// Imported entries may store their duration, which is used
+			// instead of the time since the previous entry
+			entry.Start = entry.Ts.Add(-e.Duration)
+			entry.End = entry.Start
+			entry.Duration = e.Duration
+		} else {
+			entry.Duration = group.share()
+		}
+		if opts.RawDurations {
+			// entries sharing an end all count from the group's start
+			if !newSession {
+				previous = group.start
+			}
+			entry.Raw = &RawDuration{
+				Previous: previous,
+				End:      entry.Ts,
+				Duration: entry.Duration,
+				DayReset: newSession,
+				Siblings: group.n,
+			}
+		}
+		if opts.Round > 0 {
+			entry.Duration = entry.Duration.Round(opts.Round)
+		}
 
 		*report.previous = entry.Ts
+		// Filters only apply after the duration is known, since it
+		// depends on the previous entry whether or not that is included
+		report.considered++
+		if !opts.includes(entry) {
+			report.filtered++
+			continue
+		}
+		if opts.EntriesOnly {
+			report.Entries = append(report.Entries, *entry)
+			continue
+		}
 		// Use else if to make it clear we only process the event's
 		// duration one time
 		if entry.Ignore == false && entry.Brk == false {
 			report.TaskHrs += entry.Duration
+			if b.isBillable(entry) {
+				report.BillableHrs += entry.Duration
+			}
 		} else if entry.Ignore == true && entry.Brk == false {
 			report.IgnoreHrs += entry.Duration
 		} else if entry.Ignore == false && entry.Brk == true {
-			report.BrkHrs += entry.Duration
+			settings := b.config.settings
+			entry.OffHours = opts.ExcludeWeekendBreaks && offHours(entry, settings.DayStart, settings.DayEnd)
+			if !entry.OffHours {
+				report.BrkHrs += entry.Duration
+				report.addBreak(entry)
+				if opts.BreakPolicy && entry.Duration < settings.PaidBreakLimit {
+					report.payBreak(entry, b.isBillable(entry))
+				}
+			}
 		} else if entry.Ignore == true && entry.Brk == true {
-			return "", errors.New("entry has both break and ignore set to true")
+			return "", withCode(CodeCorrupt, errors.New("entry has both break and ignore set to true"))
 		}
 		report.Entries = append(report.Entries, *entry)
 
 	}
+	report.BrkCategories = categorizedBreaks(report.BrkCategories)
+	if len(report.Entries) == 0 {
+		report.Message = fmt.Sprintf("No entries found between %s and %s", report.From.Format("2006-01-02"), report.To.AddDate(0, 0, -1).Format("2006-01-02"))
+	}
+	if !opts.EntriesOnly {
+		report.Switches, report.DaySwitches = taskSwitches(report.Entries)
+	}
+	if opts.FillGaps {
+		settings := b.config.settings
+		fillGaps(&report, stamps, settings.DayStart, settings.DayEnd)
+	}
+	if opts.RunningBalance {
+		report.Days = runningBalance(report, b.config.settings.ExpectedHours)
+		if len(report.Days) > 0 {
+			report.Balance = report.Days[len(report.Days)-1].Balance
+		}
+	}
+	if opts.IncludeEmptyDays {
+		report.AllDays = allDays(report)
+	}
+	if opts.AnnotateOvertime {
+		report.OvertimeDays, report.OvertimeHrs = overtime(report.Entries, b.config.settings.ExpectedHours)
+	}
+	if opts.ProjectTree {
+		report.Projects = projectTree(report.Entries)
+	}
+	if len(opts.GroupBy) > 0 {
+		report.Groups = groupTotals(report.Entries, opts.GroupBy, b.config.settings.WeekStart)
+	}
+	if opts.Distribution {
+		report.Distribution = distribution(report.Entries)
+	}
+	if opts.AccountFor > 0 {
+		report.Accounting = accountFor(report, opts.AccountFor, opts.AccountTolerance)
+	}
+	if opts.Calendar {
+		settings := b.config.settings
+		report.Calendar = calendar(report, settings.ExpectedHours, settings.WeekStart, settings.Locale)
+	}
+	if opts.Estimates {
+		report.Estimates = estimateTotals(report.Entries)
+	}
+	if opts.Invoice != "" {
+		report.Invoice = b.buildInvoice(report, opts.Invoice)
+	}
+	if opts.AccuracyCheck {
+		settings := b.config.settings
+		report.Suspects = accuracyCheck(report.Entries, settings.MinDuration, settings.MaxDuration)
+	}
 	f := FormatText
 	if format == "json" {
 		f = FormatJSON
@@ -360,79 +1101,580 @@ func (b *Backend) Report(start, end string, format string) (output string, err e
 	if format == "fc" {
 		f = FormatFC
 	}
+	if format == "clockify" {
+		f = FormatClockify
+	}
+	if format == "xlsx" {
+		f = FormatXLSX
+	}
+	if format == "org" {
+		f = FormatOrg
+	}
+	if format == "summary" {
+		f = FormatSummary
+	}
+	if format == "prometheus-pushgateway" {
+		f = FormatPushgateway
+	}
+	if format == "kimai" {
+		f = FormatKimai
+	}
+	if format == "csv" {
+		f = FormatCSV
+	}
+	if format == "markdown" {
+		f = FormatMarkdown
+	}
+	if format == "ics" {
+		f = FormatICS
+	}
+	err = sortReportEntries(report.Entries, opts.Sort)
+	if err != nil {
+		return "", err
+	}
+	if opts.Limit > 0 && len(report.Entries) > opts.Limit {
+		report.TotalEntries = len(report.Entries)
+		if opts.Tail {
+			report.Entries = report.Entries[len(report.Entries)-opts.Limit:]
+		} else {
+			report.Entries = report.Entries[:opts.Limit]
+		}
+	}
+	if opts.DisplayZone != nil {
+		inZone(report.Entries, opts.DisplayZone)
+		report.Timezone = opts.DisplayZone.String()
+	}
 	b.lastReport = &report
+	if b.config.settings.ReportHook != "" && !opts.NoHook {
+		return b.runReportHook(report)
+	}
 	output, err = b.formatReport(report, formatType(f))
 	if err != nil {
 		return "", err
 	}
+	if opts.Email && f == FormatText {
+		output = b.emailReport(report, output)
+	}
 	return output, nil
 }
 
+// Delete removes the entry with the given full or short ID from the
+// timesheet and returns it
+// A short ID must match a single entry.  Deleting the last entry would
+// leave an empty timesheet, so it needs force.
+func (b *Backend) Delete(id string, force bool) (*SavedEntry, error) {
+	var deleted *SavedEntry
+	err := b.updateEntries(func(data *SavedItems) (bool, error) {
+		match, err := findEntry(data.Entries, id)
+		if err != nil {
+			return false, err
+		}
+		if len(data.Entries) == 1 && !force {
+			return false, withCode(CodeParse, errors.Errorf("%s is the only entry in the timesheet - delete it with --force", id))
+		}
+		entry := data.Entries[match]
+		deleted = &entry
+		data.Entries = append(data.Entries[:match], data.Entries[match+1:]...)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// findEntry returns the index of the entry with the given full or short
+// ID, which must match a single entry
+func findEntry(entries []SavedEntry, id string) (int, error) {
+	match := -1
+	for i, e := range entries {
+		if e.ID != id && shortID(e.ID) != id {
+			continue
+		}
+		if match >= 0 {
+			return -1, withCode(CodeParse, errors.Errorf("ID %s matches more than one entry - use the full ID", id))
+		}
+		match = i
+	}
+	if match < 0 {
+		return -1, withCode(CodeNotFound, errors.Errorf("no entry with ID %s", id))
+	}
+	return match, nil
+}
+
+// SetBillable marks the entry with the given full or short ID as
+// billable with a '$' modifier, or as not billable with '$0', replacing
+// any billable modifier it had, and returns the updated entry
+func (b *Backend) SetBillable(id string, billable bool) (*SavedEntry, error) {
+	marker := "$0"
+	if billable {
+		marker = "$"
+	}
+	var updated *SavedEntry
+	err := b.updateEntries(func(data *SavedItems) (bool, error) {
+		for i, e := range data.Entries {
+			if e.ID != id && shortID(e.ID) != id {
+				continue
+			}
+			words := []string{}
+			for _, word := range strings.Fields(e.Task) {
+				if word != "$" && word != "$0" {
+					words = append(words, word)
+				}
+			}
+			// keep a break or ignore modifier last, where it is expected
+			n := len(words)
+			if n > 0 && (words[n-1] == "**" || words[n-1] == "***") {
+				words = append(words[:n-1], marker, words[n-1])
+			} else {
+				words = append(words, marker)
+			}
+			task := strings.Join(words, " ")
+			changed := task != e.Task
+			data.Entries[i].Task = task
+			if changed {
+				data.Entries[i].Modified = b.now()
+			}
+			updated = &data.Entries[i]
+			return changed, nil
+		}
+		return false, withCode(CodeNotFound, errors.Errorf("no entry with ID %s", id))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// SetBreak adds or removes the '**' break modifier on the task of the
+// entry with the given full or short ID and returns the updated entry
+// Marking an ignored entry as a break replaces its '***' modifier.
+func (b *Backend) SetBreak(id string, brk bool) (*SavedEntry, error) {
+	var updated *SavedEntry
+	err := b.updateEntries(func(data *SavedItems) (bool, error) {
+		i, err := findEntry(data.Entries, id)
+		if err != nil {
+			return false, err
+		}
+		words := []string{}
+		categorized := false
+		for _, word := range strings.Fields(data.Entries[i].Task) {
+			if strings.HasPrefix(word, BreakCategoryPrefix) {
+				// a categorized break is already a break
+				categorized = brk
+				if !brk {
+					continue
+				}
+			}
+			if word == "**" || (brk && word == "***") {
+				continue
+			}
+			words = append(words, word)
+		}
+		if brk && !categorized {
+			words = append(words, "**")
+		}
+		task := strings.Join(words, " ")
+		changed := task != data.Entries[i].Task
+		data.Entries[i].Task = task
+		if changed {
+			data.Entries[i].Modified = b.now()
+		}
+		updated = &data.Entries[i]
+		return changed, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
 // Stretch append current timestamp to end of timesheet and copy previous task
-// fp is opened in append mode, so seek to beginning of file first
-func (b *Backend) Stretch() error {
-	r, err := ioutil.ReadFile(b.config.omwFile)
+// Returns the new entry and how long the stretched task has now run, from
+// the entry before the one that was copied up to now
+func (b *Backend) Stretch() (*SavedEntry, time.Duration, error) {
+	data, err := b.readEntries()
 	if err != nil {
-		return err
+		return nil, 0, err
+	}
+
+	if len(data.Entries) == 0 {
+		return nil, 0, withCode(CodeNotFound, errors.New("no entries to stretch"))
+	}
+	sortEntries(data.Entries, false)
+	lastEntry := data.Entries[len(data.Entries)-1]
+	if lastEntry.Task == "" {
+		return nil, 0, errors.New("missing task description for stretch")
+	}
+	start := lastEntry.End
+	if len(data.Entries) > 1 {
+		start = data.Entries[len(data.Entries)-2].End
+	}
+	entry, err := b.addEntry(lastEntry.Task)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entry, entry.End.Sub(start), nil
+}
+
+// readEntries loads the timesheet without modifying it
+// The file is opened read-only and only a shared lock is attempted, so
+// reports also work against archived or snapshotted files on read-only
+// mounts.  Exclusive locks are reserved for operations that write.
+// The parsed entries are cached until the file changes.
+func (b *Backend) readEntries() (*SavedItems, error) {
+	info, err := os.Stat(b.config.omwFile)
+	if err != nil {
+		return nil, err
+	}
+	if data := b.cache.get(info); data != nil {
+		return data, nil
+	}
+	data, err := readTimesheet(b.config.omwFile)
+	if err != nil {
+		return nil, err
+	}
+	b.cache.put(info, data)
+	return data, nil
+}
+
+// readTimesheet reads the timesheet at path, taking a shared lock if it
+// can so that it doesn't see a half written file
+func readTimesheet(path string) (*SavedItems, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	fileLock := flock.New(path)
+	locked, err := fileLock.TryRLock()
+	if err == nil && locked {
+		defer fileLock.Unlock()
+	}
+	return parseTimesheet(fp)
+}
+
+// parseTimesheet reads timesheet TOML from r
+func parseTimesheet(r io.Reader) (*SavedItems, error) {
+	input, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
 	}
 	data := SavedItems{}
-	err = toml.Unmarshal(r, &data)
+	err = toml.Unmarshal(input, &data)
+	if err != nil {
+		return nil, withCode(CodeCorrupt, errors.Wrap(err, "can't unmarshal data"))
+	}
+	return &data, nil
+}
+
+// updateEntries holds the file lock while update modifies the timesheet
+// The timesheet is only rewritten if update reports a change.
+func (b *Backend) updateEntries(update func(*SavedItems) (bool, error)) error {
+	fileLock := flock.New(b.config.omwFile)
+	locked, err := fileLock.TryLock()
+	defer fileLock.Unlock()
 	if err != nil {
+		return withCode(CodeLock, errors.Wrap(err, "unable to get file lock"))
+	}
+	if !locked {
+		return withCode(CodeLock, errors.New("unable to get file lock"))
+	}
+
+	data, err := b.readEntries()
+	if err != nil {
+		return err
+	}
+	changed, err := update(data)
+	if err != nil || !changed {
 		return err
 	}
+	return b.writeEntries(data)
+}
 
-	lastEntry := data.Entries[len(data.Entries)-1]
-	if lastEntry.Task == "" {
-		return errors.New("missing task description for stretch")
+// writeEntries replaces the timesheet with data
+// The caller must hold the file lock.  The current file is copied to a
+// .bak backup first and the new data is written to a temporary file
+// that is renamed over the original, so the timesheet is never left
+// half written.
+func (b *Backend) writeEntries(data *SavedItems) error {
+	if b.config.settings.NewestFirst {
+		sortEntries(data.Entries, true)
 	}
-	err = b.addEntry(lastEntry.Task)
+	dataBytes, err := toml.Marshal(data)
 	if err != nil {
+		return errors.Wrap(err, "can't marshal data")
+	}
+
+	input, err := ioutil.ReadFile(b.config.omwFile)
+	if err != nil {
+		return errors.Wrap(err, "reading backup file")
+	}
+	backup := fmt.Sprintf("%s.bak", b.config.omwFile)
+	err = writeFileSync(backup, input, 0644)
+	if err != nil {
+		return errors.Wrap(err, "writing backup file")
+	}
+
+	err = replaceFile(b.config.omwFile, dataBytes)
+	if err != nil {
+		b.cache.invalidate()
 		return err
 	}
+	info, err := os.Stat(b.config.omwFile)
+	if err != nil {
+		b.cache.invalidate()
+		return nil
+	}
+	// cache what a later read would see, as TOML drops the fractional
+	// seconds that data may still have
+	if written, err := parseTimesheet(bytes.NewReader(dataBytes)); err == nil {
+		b.cache.put(info, written)
+	} else {
+		b.cache.invalidate()
+	}
+	writeStatus(statusPath(b.config.omwFile), lastStatus(data.Entries), info)
+	return nil
+}
+
+// replaceFile writes dataBytes to a temporary file next to path and
+// renames it over path
+func replaceFile(path string, dataBytes []byte) error {
+	pat := fmt.Sprintf("%s*", filepath.Base(path))
+	tmpFile, err := ioutil.TempFile(filepath.Dir(path), pat)
+	if err != nil {
+		return errors.Wrap(err, "creating temporary file")
+	}
+	tmpPath := tmpFile.Name()
+	_, err = tmpFile.Write(dataBytes)
+	if err == nil {
+		err = tmpFile.Sync()
+	}
+	tmpFile.Close()
+	if err == nil {
+		err = os.Chmod(tmpPath, 0644)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return errors.Wrap(err, "saving new data")
+	}
+	err = renameSync(tmpPath, path)
+	if err != nil {
+		os.Remove(tmpPath)
+		return errors.Wrap(err, "replacing data file")
+	}
+	return nil
+}
+
+// ShortIDLength is the number of characters of an entry ID that reports
+// show, enough to tell the entries of a timesheet apart
+const ShortIDLength = 8
+
+// shortID returns the start of id that reports show
+func shortID(id string) string {
+	if len(id) > ShortIDLength {
+		return id[:ShortIDLength]
+	}
+	return id
+}
+
+// now returns the current time in the timezone timestamps are stored in
+func (b *Backend) now() time.Time {
+	if b.config.settings.StoreUTC {
+		return time.Now().UTC()
+	}
+	return time.Now()
+}
+
+// markModified sets the modified time of every entry in edited that is
+// new or differs from the entry with the same ID in original
+func markModified(original, edited []SavedEntry, now time.Time) int {
+	changed := 0
+	before := make(map[string]SavedEntry, len(original))
+	for _, e := range original {
+		before[e.ID] = e
+	}
+	for i, e := range edited {
+		old, ok := before[e.ID]
+		if ok && old.Task == e.Task && old.End.Equal(e.End) {
+			continue
+		}
+		edited[i].Modified = now
+		changed++
+	}
+	return changed
+}
+
+// sameDay reports whether a and b fall on the same calendar day
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// isHello reports whether entry is a start of day marker added by Hello()
+func isHello(entry *ReportEntry) bool {
+	return strings.EqualFold(entry.Title, "hello")
+}
+
+// sortEntries orders entries by timestamp, oldest first unless
+// newestFirst is set.  Entries with equal timestamps keep their order.
+func sortEntries(entries []SavedEntry, newestFirst bool) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if newestFirst {
+			return entries[i].End.After(entries[j].End)
+		}
+		return entries[i].End.Before(entries[j].End)
+	})
+}
+
+// periodEntries returns the entries ending from start to end inclusive,
+// found by binary search of entries sorted oldest first
+func periodEntries(entries []SavedEntry, start, end time.Time) []SavedEntry {
+	first := sort.Search(len(entries), func(i int) bool { return !entries[i].End.Before(start) })
+	last := sort.Search(len(entries), func(i int) bool { return entries[i].End.After(end) })
+	if last < first {
+		return nil
+	}
+	return entries[first:last]
+}
+
+// lastBefore returns the last entry with a task that ends before start
+func lastBefore(entries []SavedEntry, start time.Time) *SavedEntry {
+	i := sort.Search(len(entries), func(i int) bool { return !entries[i].End.Before(start) })
+	for i--; i >= 0; i-- {
+		if entries[i].Task != "" {
+			return &entries[i]
+		}
+	}
 	return nil
 }
 
 // addEntry seeks to end of file and appends a formatted string
 // will create a new empty file if file is missing
-func (b *Backend) addEntry(s string) error {
+// Returns the entry that was saved
+func (b *Backend) addEntry(s string) (*SavedEntry, error) {
+	entries, err := b.addEntries([]string{s})
+	if err != nil {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+// addEntries saves one entry for each of tasks, all with the current time
+func (b *Backend) addEntries(tasks []string) ([]SavedEntry, error) {
 	fp, err := os.OpenFile(b.config.omwFile, os.O_APPEND|os.O_RDWR|os.O_CREATE, 0644)
 	if err != nil {
-		return errors.Wrapf(err, "can't open or create %s: %q", b.config.omwFile, err)
+		return nil, errors.Wrapf(err, "can't open or create %s: %q", b.config.omwFile, err)
 	}
 	defer fp.Close()
 	data := SavedItems{}
-	entry := SavedEntry{}
-	entry.ID = uuid.New().String()
-	entry.End = time.Now()
-	entry.Task = s
-	data.Entries = append(data.Entries, entry)
+	now := b.now()
+	for _, task := range tasks {
+		entry := SavedEntry{}
+		entry.ID = uuid.New().String()
+		entry.End = now
+		entry.Task = task
+		entry.Modified = entry.End
+		data.Entries = append(data.Entries, entry)
+	}
+	// newest first means the entries go at the top, so the whole
+	// timesheet has to be rewritten rather than appended to
+	if b.config.settings.NewestFirst {
+		fp.Close()
+		err = b.updateEntries(func(saved *SavedItems) (bool, error) {
+			saved.Entries = append(append([]SavedEntry{}, data.Entries...), saved.Entries...)
+			if err := b.writeEntries(saved); err != nil {
+				return false, err
+			}
+			// written here rather than by updateEntries so the log is
+			// appended to while the lock is still held, as below
+			if err := b.appendPlainLog(data.Entries); err != nil {
+				log.Printf("can't write plaintext log: %v", err)
+			}
+			return false, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		b.notifyWebhooks(data.Entries)
+		return data.Entries, nil
+	}
 	entriesBytes, err := toml.Marshal(data)
 	if err != nil {
-		return errors.Wrap(err, "can't marshal data")
+		return nil, errors.Wrap(err, "can't marshal data")
 	}
 	toSave := string(entriesBytes)
 	fileLock := flock.New(b.config.omwFile)
 	locked, err := fileLock.TryLock()
 	defer fileLock.Unlock()
 	if err != nil {
-		return errors.Wrap(err, "unable to get file lock")
+		return nil, withCode(CodeLock, errors.Wrap(err, "unable to get file lock"))
 	}
 	if !locked {
-		return errors.New("unable to get file lock")
+		return nil, withCode(CodeLock, errors.New("unable to get file lock"))
 	}
 	_, err = fp.WriteString(toSave)
+	if err == nil {
+		err = fp.Sync()
+	}
+	b.cache.invalidate()
 	if err != nil {
-		return errors.Wrap(err, "error saving new data")
+		return nil, errors.Wrap(err, "error saving new data")
 	}
-	return nil
+	// still holding the lock keeps the log in the same order as the
+	// timesheet
+	if err := b.appendPlainLog(data.Entries); err != nil {
+		log.Printf("can't write plaintext log: %v", err)
+	}
+	b.refreshStatus(data.Entries)
+	fileLock.Unlock()
+	b.notifyWebhooks(data.Entries)
+	return data.Entries, nil
 }
 
 func (b *Backend) formatReport(report Report, format formatType) (string, error) {
+	if format == FormatJSON && report.Options.EntriesOnly {
+		output, err := json.Marshal(report.Entries)
+		return string(output), err
+	}
 	if format == FormatJSON {
 		output, err := json.Marshal(report)
 		return string(output), err
 	}
 
+	if format == FormatClockify {
+		return formatClockify(report, b.isBillable)
+	}
+
+	if format == FormatKimai {
+		return formatKimai(report)
+	}
+
+	if format == FormatCSV {
+		return formatCSV(report)
+	}
+
+	if format == FormatXLSX {
+		return formatXLSX(report)
+	}
+
+	if format == FormatICS {
+		return formatICS(report, time.Now()), nil
+	}
+
+	if format == FormatOrg {
+		return formatOrg(report)
+	}
+
+	if format == FormatSummary {
+		return fmt.Sprintf("%.2f", report.TaskHrs.Hours()), nil
+	}
+
+	if format == FormatPushgateway {
+		return formatPushgateway(report), nil
+	}
+
 	entries := []ReportEntry{}
 	if format == FormatFC {
 		for _, entry := range report.Entries {
@@ -443,8 +1685,12 @@ func (b *Backend) formatReport(report Report, format formatType) (string, error)
 			if entry.Ignore {
 				classes = append(classes, "ignoreEntry")
 			}
+			if entry.Unaccounted {
+				classes = append(classes, "unaccountedEntry")
+			}
 
 			entries = append(entries, ReportEntry{
+				ID:         entry.ID,
 				Start:      entry.Start,
 				End:        entry.Start.Add(entry.Duration),
 				Title:      entry.Title,
@@ -459,27 +1705,143 @@ func (b *Backend) formatReport(report Report, format formatType) (string, error)
 		return string(output), err
 	}
 
-	// fallback to text format
-	reportTmpl, err := template.New("report").Parse(TemplateString)
+	// fallback to text format, or Markdown which is written the same way
+	if len(report.Entries) == 0 && report.Message != "" && !report.Options.listsDays() {
+		return report.Message + "\n", nil
+	}
+	if report.Options.AggregateBreaks || report.Options.AggregateIgnored {
+		report.Entries = rollupEntries(report.Entries, report.Options.AggregateBreaks, report.Options.AggregateIgnored)
+	}
+	if width := b.config.settings.TitleMaxLength; width > 0 {
+		report.Entries = truncateTitles(report.Entries, width)
+	}
+	if format == FormatMarkdown {
+		return formatMarkdown(report, b.config.settings.Locale), nil
+	}
+	if report.Options.Plain {
+		return formatPlain(report), nil
+	}
+	tmpl, err := b.reportTemplate(report)
+	if err != nil {
+		return "", err
+	}
+	reportTmpl, err := template.New("report").Funcs(b.templateFuncs()).Parse(tmpl)
 	if err != nil {
 		return "", err
 	}
-	err = reportTmpl.Execute(os.Stdout, report)
+	var buf bytes.Buffer
+	err = reportTmpl.Execute(&buf, report)
 	if err != nil {
-		panic(err)
+		return "", errors.Wrap(err, "can't render report template")
+	}
+	return buf.String(), nil
+}
+
+// ansiStyles maps the style names available to report templates to their
+// ANSI escape codes
+var ansiStyles = map[string]string{
+	"bold":   "\033[1m",
+	"dim":    "\033[2m",
+	"green":  "\033[32m",
+	"yellow": "\033[33m",
+	"reset":  "\033[0m",
+}
+
+// templateFuncs returns the functions available to report templates
+// {{style "name"}} emits the escape code for a style from ansiStyles, or
+// nothing at all when color is disabled
+func (b *Backend) templateFuncs() template.FuncMap {
+	color := b.config.settings.Color
+	currency := b.config.settings.Currency
+	locale := b.config.settings.Locale
+	return template.FuncMap{
+		"weekday": func(t time.Time) string {
+			return weekdayName(locale, t)
+		},
+		"month": func(t time.Time) string {
+			return monthName(locale, t)
+		},
+		"money":    currency.Format,
+		"pad":      pad,
+		"padRight": padRight,
+		"bar":      bar,
+		"shortID":  shortID,
+		"style": func(name string) string {
+			if !color {
+				return ""
+			}
+			return ansiStyles[name]
+		},
 	}
-	return "", nil
 }
 
+// taskPattern splits the title of a task from a trailing break or ignore
+// marker.  Titles may be in any script and include symbols such as emoji.
+var taskPattern = regexp.MustCompile(`(?P<task>[\p{L}\p{M}\p{N}\p{So},._+:@%\/-]+[\p{L}\p{M}\p{N}\p{So},._+:@%\/\- ]*) ?(?P<mod>\*\*\*?)*`)
+
+// parseEntry splits a saved task string into a ReportEntry
+// Tokens are pulled out of the task before the title is matched:
+// @name    - the project the task belongs to
+// #name    - a tag, which may be repeated
+// !name    - the client the task is done for
+// $        - the task is billable
+// $0       - the task is not billable
+// A trailing '**' marks a break and '***' marks time to ignore
 func (b *Backend) parseEntry(s string) (*ReportEntry, error) {
-	re := regexp.MustCompile(`(?P<task>[a-zA-Z0-9,._+:@%\/-]+[a-zA-Z0-9,._+:@%\/\-\t ]*) ?(?P<mod>\*\*\*?)*`)
-	matches := re.FindStringSubmatch(s)
+	entry := &ReportEntry{}
+	words := []string{}
+	for _, word := range strings.Fields(s) {
+		switch {
+		case word == "$":
+			billable := true
+			entry.Billable = &billable
+		case word == "$0":
+			billable := false
+			entry.Billable = &billable
+		case len(word) > len(BreakCategoryPrefix) && strings.HasPrefix(word, BreakCategoryPrefix):
+			entry.Brk = true
+			entry.BrkCategory = word[len(BreakCategoryPrefix):]
+		case len(word) > 1 && word[0] == '@':
+			entry.Project = word[1:]
+		case len(word) > 1 && word[0] == '!':
+			entry.Client = word[1:]
+		case len(word) > 1 && word[0] == '#':
+			entry.Tags = append(entry.Tags, word[1:])
+		case strings.HasPrefix(word, EstimatePrefix):
+			// Add() rejects bad estimates, so one here was typed by
+			// hand and is kept as part of the title
+			estimate, err := parseEstimate(word)
+			if err != nil {
+				words = append(words, word)
+				break
+			}
+			entry.Estimate = estimate
+		default:
+			words = append(words, word)
+		}
+	}
+	// A task that is only tags, such as "#standup", is titled by them
+	if len(entry.Tags) > 0 && (len(words) == 0 || len(words) == 1 && (words[0] == "**" || words[0] == "***")) {
+		words = append(append([]string{}, entry.Tags...), words...)
+	}
+	// Add() rejects tasks without a title, but one edited down to just
+	// a modifier is kept as an untitled break or ignore rather than
+	// skipped, which would give its time to the next entry
+	switch rest := strings.Join(words, " "); {
+	case rest == "**":
+		entry.Brk = true
+		return entry, nil
+	case rest == "***":
+		entry.Ignore = true
+		return entry, nil
+	case rest == "" && entry.Brk:
+		return entry, nil
+	}
+	matches := taskPattern.FindStringSubmatch(strings.Join(words, " "))
 	if matches == nil {
 		return nil, errors.New("invalid string")
 	}
-	entry := &ReportEntry{
-		Title: matches[1],
-	}
+	entry.Title = strings.TrimSpace(matches[1])
 	if matches[2] == "**" {
 		entry.Brk = true
 	}
@@ -489,6 +1851,25 @@ func (b *Backend) parseEntry(s string) (*ReportEntry, error) {
 	return entry, nil
 }
 
+// isBillable resolves whether a task counts toward billable hours
+// An explicit $ or $0 on the entry wins over the project's configured
+// default, then the closest parent project's, then the client's, then the
+// global default
+func (b *Backend) isBillable(entry *ReportEntry) bool {
+	if entry.Billable != nil {
+		return *entry.Billable
+	}
+	for _, project := range projectPaths(entry.Project) {
+		if billable, ok := b.config.settings.BillableProjects[strings.ToLower(project)]; ok {
+			return billable
+		}
+	}
+	if client, ok := b.config.settings.Clients[strings.ToLower(entry.Client)]; ok && client.Billable != nil {
+		return *client.Billable
+	}
+	return b.config.settings.Billable
+}
+
 // Create an instance of the structures that operate on Omw data
 func Create(fp *os.File, omwDir, omwFile string) *Backend {
 	return &Backend{
@@ -502,6 +1883,87 @@ func Create(fp *os.File, omwDir, omwFile string) *Backend {
 	}
 }
 
+// dayTotals sums the entries of a report by the calendar day on which
+// each task ended
+// Unaccounted gaps added by ReportOptions.FillGaps weren't worked, so
+// they are left out, as are breaks excluded by
+// ReportOptions.ExcludeWeekendBreaks.  Breaks paid under
+// ReportOptions.BreakPolicy count as task hours.
+func dayTotals(entries []ReportEntry) []DayTotal {
+	days := []DayTotal{}
+	for _, entry := range entries {
+		if entry.Unaccounted || entry.OffHours {
+			continue
+		}
+		y, m, d := entry.Ts.Date()
+		date := time.Date(y, m, d, 0, 0, 0, 0, entry.Ts.Location())
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(date) {
+			days = append(days, DayTotal{Date: date})
+		}
+		day := &days[len(days)-1]
+		if entry.Ignore {
+			day.IgnoreHrs += entry.Duration
+		} else if entry.Brk && !entry.Paid {
+			day.BrkHrs += entry.Duration
+		} else {
+			day.TaskHrs += entry.Duration
+		}
+	}
+	return days
+}
+
+// runningBalance walks every day of the report period up to today and
+// accumulates worked minus expected hours
+// Weekdays are expected to be working days even when nothing was tracked
+// so that a day off is deducted from the balance, while weekend days only
+// appear if work was tracked on them
+func runningBalance(report Report, expected time.Duration) []DayTotal {
+	tracked := make(map[string]DayTotal)
+	for _, day := range dayTotals(report.Entries) {
+		tracked[day.Date.Format("2006-01-02")] = day
+	}
+	days := []DayTotal{}
+	end := report.To
+	if now := time.Now(); now.Before(end) {
+		end = now
+	}
+	var balance time.Duration
+	for date := report.From; date.Before(end); date = date.AddDate(0, 0, 1) {
+		day, ok := tracked[date.Format("2006-01-02")]
+		if !ok {
+			day = DayTotal{Date: date}
+		}
+		if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+			day.Expected = expected
+		}
+		if !ok && day.Expected == 0 {
+			continue
+		}
+		balance += day.TaskHrs - day.Expected
+		day.Balance = balance
+		days = append(days, day)
+	}
+	return days
+}
+
+// allDays returns the totals of every day of the report period, with
+// zero totals for days without entries
+func allDays(report Report) []DayTotal {
+	tracked := make(map[string]DayTotal)
+	for _, day := range dayTotals(report.Entries) {
+		tracked[day.Date.Format("2006-01-02")] = day
+	}
+	days := []DayTotal{}
+	for date := report.From; date.Before(report.To); date = date.AddDate(0, 0, 1) {
+		day, ok := tracked[date.Format("2006-01-02")]
+		if !ok {
+			day = DayTotal{Date: date}
+		}
+		days = append(days, day)
+	}
+	return days
+}
+
 // runCommand Executes cmd and handles any output
 func runCommand(cmd *exec.Cmd) error {
 	err := cmd.Run()
@@ -520,17 +1982,18 @@ func runCommand(cmd *exec.Cmd) error {
 //
 // It does not:
 // 1. Check for in-order task times
-func validateEdit(fn string) (*SavedItems, error) {
+func validateEdit(fn string) (*SavedItems, int, error) {
 	keys := make(map[string]bool)
 	data := SavedItems{}
 	r, err := ioutil.ReadFile(fn)
 	if err != nil {
-		return nil, errors.Wrap(err, "reading temporary file")
+		return nil, 0, errors.Wrap(err, "reading temporary file")
 	}
 	err = toml.Unmarshal(r, &data)
 	if err != nil {
-		return nil, errors.Wrap(err, "TOML formatting error please try again")
+		return nil, 0, withCode(CodeCorrupt, errors.Wrap(err, "TOML formatting error please try again"))
 	}
+	fixed := 0
 
 	for i, e := range data.Entries {
 		if _, exists := keys[e.ID]; exists {
@@ -539,9 +2002,10 @@ func validateEdit(fn string) (*SavedItems, error) {
 			log.Printf("New ID = %s", newID)
 			keys[e.ID] = true
 			data.Entries[i].ID = newID
+			fixed++
 			continue
 		}
 		keys[e.ID] = false
 	}
-	return &data, nil
+	return &data, fixed, nil
 }
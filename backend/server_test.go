@@ -4,38 +4,103 @@ package backend
 
 import (
 	"context"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
+// newTestBackend returns a Backend using a temporary timesheet holding
+// data, and a function to remove it
+func newTestBackend(t testing.TB, data string) (*Backend, func()) {
+	dir, err := ioutil.TempDir("", "omw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	omwFile := filepath.Join(dir, "omw.toml")
+	err = ioutil.WriteFile(omwFile, []byte(data), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Create(nil, dir, omwFile), func() { os.RemoveAll(dir) }
+}
+
+// localUTC makes UTC the local timezone, so fixtures written in UTC fall
+// on the same days and weekdays whatever the machine's timezone, and
+// returns a func that restores it
+func localUTC() func() {
+	local := time.Local
+	time.Local = time.UTC
+	return func() { time.Local = local }
+}
+
 func TestBackend_Add(t *testing.T) {
-	type fields struct {
-		ctx    context.Context
-		config *config
-		fp     *os.File
-		worker *worker
+	tests := []struct {
+		name    string
+		args    []string
+		title   string
+		wantErr bool
+	}{
+		{"plain", []string{"write", "report"}, "write report", false},
+		{"CJK", []string{"会议"}, "会议", false},
+		{"accented", []string{"réunion", "client", "@acme"}, "réunion client", false},
+		{"emoji", []string{"🚀", "deploy", "**"}, "🚀 deploy", false},
+		{"modifiers only", []string{"@acme", "$"}, "", true},
+		{"break marker only", []string{"**"}, "", true},
 	}
-	type args struct {
-		args []string
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, cleanup := newTestBackend(t, "")
+			defer cleanup()
+			entries, err := b.Add(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Backend.Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			entry, err := b.parseEntry(entries[0].Task)
+			if err != nil || entry.Title != tt.title {
+				t.Errorf("Backend.Add() saved %q titled %q, %v, want %q", entries[0].Task, entry.Title, err, tt.title)
+			}
+		})
 	}
+}
+
+func TestBackend_Add_plainLog(t *testing.T) {
 	tests := []struct {
-		name   string
-		fields fields
-		args   args
+		name        string
+		newestFirst bool
 	}{
-		// TODO: Add test cases.
+		{"appended", false},
+		{"newest first", true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			b := &Backend{
-				ctx:    tt.fields.ctx,
-				config: tt.fields.config,
-				fp:     tt.fields.fp,
-				worker: tt.fields.worker,
+			b, cleanup := newTestBackend(t, "")
+			defer cleanup()
+			s := b.Settings()
+			s.PlainLog = true
+			s.NewestFirst = tt.newestFirst
+			b.Configure(s)
+			for _, task := range []string{"hello", "write report"} {
+				if _, err := b.Add(strings.Fields(task)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := ioutil.ReadFile(filepath.Join(filepath.Dir(b.config.omwFile), "omw-plain.log"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSpace(string(got)), "\n")
+			if len(lines) != 2 || !strings.HasSuffix(lines[0], " — hello") || !strings.HasSuffix(lines[1], " — write report") {
+				t.Errorf("plaintext log = %q, want hello then write report", got)
 			}
-			b.Add(tt.args.args)
 		})
 	}
 }
@@ -173,35 +238,1300 @@ func TestBackend_Report(t *testing.T) {
 				fp:     tt.fields.fp,
 				worker: tt.fields.worker,
 			}
-			b.Report(tt.args.start, tt.args.end, "text")
+			b.Report(tt.args.start, tt.args.end, "text", ReportOptions{})
 		})
 	}
 }
 
-func TestBackend_Stretch(t *testing.T) {
-	type fields struct {
-		ctx    context.Context
-		config *config
-		fp     *os.File
-		worker *worker
+func TestBackend_Report_dateRange(t *testing.T) {
+	data := `
+[[entries]]
+  id = "1"
+  end = 2024-01-15T09:00:00Z
+  task = "hello"
+`
+	tests := []struct {
+		name    string
+		start   string
+		end     string
+		wantErr bool
+	}{
+		{"swapped dates", "2024-02-01", "2024-01-01", true},
+		{"single day", "2024-01-15", "2024-01-15", false},
+		{"range", "2024-01-01", "2024-02-01", false},
+	}
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := b.Report(tt.start, tt.end, "json", ReportOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Backend.Report() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && Code(err) != CodeParse {
+				t.Errorf("Backend.Report() error code = %s, want %s", Code(err), CodeParse)
+			}
+		})
+	}
+}
+
+func TestBackend_Report_storedDuration(t *testing.T) {
+	// an imported entry with a duration between timestamp-based entries
+	data := `
+[[entries]]
+  id = "1"
+  end = 2024-01-15T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2024-01-15T11:00:00Z
+  task = "imported review"
+  duration = "30m"
+[[entries]]
+  id = "3"
+  end = 2024-01-15T12:00:00Z
+  task = "write docs"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	_, err := b.Report("2024-01-15", "2024-01-15", "json", ReportOptions{})
+	if err != nil {
+		t.Fatalf("Backend.Report() error = %v", err)
+	}
+	report := b.LastReport()
+	want := []time.Duration{0, 30 * time.Minute, time.Hour}
+	for i, entry := range report.Entries {
+		if entry.Duration != want[i] {
+			t.Errorf("entry %s duration = %s, want %s", entry.ID, entry.Duration, want[i])
+		}
+	}
+	if start := report.Entries[1].Start.UTC().Format("15:04"); start != "10:30" {
+		t.Errorf("imported entry starts at %s, want 10:30", start)
+	}
+	if report.TaskHrs != 90*time.Minute {
+		t.Errorf("TaskHrs = %s, want 1h30m", report.TaskHrs)
 	}
+}
+
+func TestBackend_Report_tags(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T10:00:00Z
+  task = "fix login #acme"
+[[entries]]
+  id = "3"
+  end = 2020-03-02T10:30:00Z
+  task = "coffee #social **"
+[[entries]]
+  id = "4"
+  end = 2020-03-02T12:30:00Z
+  task = "invoice #billing"
+[[entries]]
+  id = "5"
+  end = 2020-03-02T13:00:00Z
+  task = "cleanup"
+`
+	tests := []struct {
+		name  string
+		tags  []string
+		task  time.Duration
+		brk   time.Duration
+		count int
+	}{
+		{"no filter", nil, 3*time.Hour + 30*time.Minute, 30 * time.Minute, 5},
+		{"one tag", []string{"acme"}, time.Hour, 0, 1},
+		{"tags are ORed", []string{"acme", "billing", "social"}, 3 * time.Hour, 30 * time.Minute, 3},
+	}
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := b.Report("2020-03-02", "2020-03-02", "json", ReportOptions{Tags: tt.tags}); err != nil {
+				t.Fatal(err)
+			}
+			r := b.LastReport()
+			if r.TaskHrs != tt.task || r.BrkHrs != tt.brk || len(r.Entries) != tt.count {
+				t.Errorf("Backend.Report() with tags %v = %s task, %s break, %d entries, want %s, %s, %d",
+					tt.tags, r.TaskHrs, r.BrkHrs, len(r.Entries), tt.task, tt.brk, tt.count)
+			}
+		})
+	}
+}
+
+func TestBackend_Report_helloStartsDay(t *testing.T) {
+	defer localUTC()()
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T17:00:00Z
+  task = "release"
+[[entries]]
+  id = "3"
+  end = 2020-03-03T00:30:00Z
+  task = "hotfix"
+[[entries]]
+  id = "4"
+  end = 2020-03-03T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "5"
+  end = 2020-03-03T09:15:00Z
+  task = "standup"
+`
+	tests := []struct {
+		name   string
+		hello  bool
+		from   string
+		task   time.Duration
+		hotfix time.Duration
+	}{
+		{"midnight", false, "2020-03-02", 16*time.Hour + 45*time.Minute, 0},
+		{"midnight, one day", false, "2020-03-03", 8*time.Hour + 45*time.Minute, 0},
+		{"hello", true, "2020-03-02", 15*time.Hour + 45*time.Minute, 7*time.Hour + 30*time.Minute},
+		{"hello, one day", true, "2020-03-03", 7*time.Hour + 45*time.Minute, 7*time.Hour + 30*time.Minute},
+	}
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := b.Settings()
+			settings.HelloStartsDay = tt.hello
+			b.Configure(settings)
+			if _, err := b.Report(tt.from, "2020-03-03", "json", ReportOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			r := b.LastReport()
+			hotfix := time.Duration(-1)
+			for _, entry := range r.Entries {
+				if entry.ID == "3" {
+					hotfix = entry.Duration
+				}
+			}
+			if r.TaskHrs != tt.task || hotfix != tt.hotfix {
+				t.Errorf("Backend.Report() = %s task, hotfix %s, want %s, %s", r.TaskHrs, hotfix, tt.task, tt.hotfix)
+			}
+		})
+	}
+}
+
+func TestBackend_Report_runningBalance(t *testing.T) {
+	defer localUTC()()
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-06T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-06T18:00:00Z
+  task = "api"
+[[entries]]
+  id = "3"
+  end = 2020-03-07T10:00:00Z
+  task = "hello"
+[[entries]]
+  id = "4"
+  end = 2020-03-07T12:00:00Z
+  task = "docs"
+[[entries]]
+  id = "5"
+  end = 2020-03-09T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "6"
+  end = 2020-03-09T15:00:00Z
+  task = "api"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	settings := b.Settings()
+	settings.ExpectedHours = 8 * time.Hour
+	b.Configure(settings)
+	if _, err := b.Report("2020-03-06", "2020-03-09", "json", ReportOptions{RunningBalance: true}); err != nil {
+		t.Fatal(err)
+	}
+	r := b.LastReport()
+	// Sunday has nothing tracked and nothing expected, so it is left out
+	want := []struct {
+		date     string
+		expected time.Duration
+		balance  time.Duration
+	}{
+		{"2020-03-06", 8 * time.Hour, time.Hour},
+		{"2020-03-07", 0, 3 * time.Hour},
+		{"2020-03-09", 8 * time.Hour, time.Hour},
+	}
+	if len(r.Days) != len(want) {
+		t.Fatalf("Backend.Report() running balance has %d days, want %d: %v", len(r.Days), len(want), r.Days)
+	}
+	for i, w := range want {
+		d := r.Days[i]
+		if d.Date.Format("2006-01-02") != w.date || d.Expected != w.expected || d.Balance != w.balance {
+			t.Errorf("day %d = %s expecting %s with balance %s, want %s expecting %s with balance %s", i, d.Date.Format("2006-01-02"), d.Expected, d.Balance, w.date, w.expected, w.balance)
+		}
+	}
+	if r.Balance != time.Hour {
+		t.Errorf("Backend.Report() final balance = %s, want 1h0m0s", r.Balance)
+	}
+}
+
+func TestBackend_Report_projects(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T10:00:00Z
+  task = "api @acme/backend"
+[[entries]]
+  id = "3"
+  end = 2020-03-02T12:00:00Z
+  task = "ui @acme/frontend"
+[[entries]]
+  id = "4"
+  end = 2020-03-02T12:30:00Z
+  task = "planning @acme"
+[[entries]]
+  id = "5"
+  end = 2020-03-02T13:30:00Z
+  task = "support @acmecorp"
+`
 	tests := []struct {
 		name    string
-		fields  fields
+		opts    ReportOptions
+		wantIDs string
+		tree    time.Duration
+	}{
+		{"sub-projects included", ReportOptions{Projects: []string{"acme"}, ProjectTree: true}, "234", 3*time.Hour + 30*time.Minute},
+		{"sub-project excluded", ReportOptions{Projects: []string{"acme"}, ExcludeProjects: []string{"acme/frontend"}, ProjectTree: true}, "24", 90 * time.Minute},
+		{"sub-project only", ReportOptions{Projects: []string{"acme/backend"}, ProjectTree: true}, "2", time.Hour},
+	}
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := b.Report("2020-03-02", "2020-03-02", "json", tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			r := b.LastReport()
+			ids := ""
+			for _, e := range r.Entries {
+				if e.Project != "" {
+					ids += e.ID
+				}
+			}
+			if ids != tt.wantIDs {
+				t.Errorf("Backend.Report() entries = %s, want %s", ids, tt.wantIDs)
+			}
+			if len(r.Projects) != 1 || r.Projects[0].Name != "acme" || r.Projects[0].TaskHrs != tt.tree {
+				t.Errorf("Backend.Report() project tree = %v, want acme rolling up %s", r.Projects, tt.tree)
+			}
+		})
+	}
+}
+
+func TestBackend_newestFirst(t *testing.T) {
+	data := `[[entries]]
+  id = "3"
+  end = 2020-03-02T11:30:00Z
+  task = "review"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T10:00:00Z
+  task = "api"
+[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	settings := b.Settings()
+	settings.NewestFirst = true
+	b.Configure(settings)
+	if _, err := b.Report("2020-03-02", "2020-03-02", "json", ReportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	r := b.LastReport()
+	if len(r.Entries) != 3 || r.Entries[1].ID != "2" || r.Entries[1].Duration != time.Hour || r.TaskHrs != 150*time.Minute {
+		t.Errorf("Backend.Report() of a newest first timesheet = %v with %s task hours, want oldest first and 2h30m0s", r.Entries, r.TaskHrs)
+	}
+	added, err := b.Add([]string{"docs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.SetBreak("2", true); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := readTimesheet(b.config.omwFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{}
+	for _, e := range saved.Entries {
+		ids = append(ids, e.ID)
+	}
+	if got, want := strings.Join(ids, ","), added[0].ID+",3,2,1"; got != want {
+		t.Errorf("timesheet order = %s, want %s", got, want)
+	}
+}
+
+func TestBackend_Report_color(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T10:00:00Z
+  task = "api"
+[[entries]]
+  id = "3"
+  end = 2020-03-02T10:30:00Z
+  task = "coffee **"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	for _, color := range []bool{true, false} {
+		settings := b.Settings()
+		settings.Color = color
+		b.Configure(settings)
+		for _, format := range []string{"text", "markdown"} {
+			out, err := b.Report("2020-03-02", "2020-03-02", format, ReportOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if styled := strings.Contains(out, "\x1b["); styled != (color && format == "text") {
+				t.Errorf("Backend.Report(%s) with Color %v styled = %v: %q", format, color, styled, out)
+			}
+		}
+	}
+}
+
+func TestBackend_Report_errorCodes(t *testing.T) {
+	valid := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+`
+	tests := []struct {
+		name string
+		data string
+		from string
+		opts ReportOptions
+		want ErrorCode
+	}{
+		{"bad date", valid, "the day after", ReportOptions{}, CodeParse},
+		{"bad sort", valid, "2020-03-02", ReportOptions{Sort: "client"}, CodeParse},
+		{"corrupt timesheet", "[[entries]\n", "2020-03-02", ReportOptions{}, CodeCorrupt},
+		{"corrupt input", valid, "2020-03-02", ReportOptions{Input: strings.NewReader("[[entries]\n")}, CodeCorrupt},
+		{"missing timesheet", "", "2020-03-02", ReportOptions{}, CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, cleanup := newTestBackend(t, tt.data)
+			defer cleanup()
+			if tt.data == "" {
+				os.Remove(b.config.omwFile)
+			}
+			_, err := b.Report(tt.from, "2020-03-02", "json", tt.opts)
+			if Code(err) != tt.want {
+				t.Errorf("Backend.Report() error = %v with code %q, want code %q", err, Code(err), tt.want)
+			}
+		})
+	}
+}
+
+func TestBackend_Report_showIDs(t *testing.T) {
+	data := `[[entries]]
+  id = "6fa459ea-ee8a-3ca4-894e-db77e160355e"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "1b4e28ba-2fa1-11d2-883f-0016d3cca427"
+  end = 2020-03-02T10:00:00Z
+  task = "api"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	for _, show := range []bool{true, false} {
+		out, err := b.Report("2020-03-02", "2020-03-02", "text", ReportOptions{ShowIDs: show})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(out, "api [1b4e28ba]"); got != show {
+			t.Errorf("Backend.Report() with ShowIDs %v shows the short ID = %v: %q", show, got, out)
+		}
+		if strings.Contains(out, "1b4e28ba-") {
+			t.Errorf("Backend.Report() shows the full ID: %q", out)
+		}
+	}
+	if _, err := b.Report("2020-03-02", "2020-03-02", "json", ReportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if r := b.LastReport(); len(r.Entries) != 2 || r.Entries[1].ID != "1b4e28ba-2fa1-11d2-883f-0016d3cca427" {
+		t.Errorf("Backend.Report() entries = %v, want the full saved IDs", r.Entries)
+	}
+}
+
+func TestBackend_Report_rawDurations(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T10:00:00Z
+  task = "api"
+[[entries]]
+  id = "3"
+  end = 2020-03-02T10:30:00Z
+  task = "review"
+[[entries]]
+  id = "4"
+  end = 2020-03-02T10:30:00Z
+  task = "email"
+[[entries]]
+  id = "5"
+  end = 2020-03-03T09:00:00Z
+  task = "hello"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2020, 3, day, hour, min, 0, 0, time.UTC)
+	}
+	want := []RawDuration{
+		{End: at(2, 9, 0), DayReset: true},
+		{Previous: at(2, 9, 0), End: at(2, 10, 0), Duration: time.Hour, Siblings: 1},
+		{Previous: at(2, 10, 0), End: at(2, 10, 30), Duration: 15 * time.Minute, Siblings: 2},
+		{Previous: at(2, 10, 0), End: at(2, 10, 30), Duration: 15 * time.Minute, Siblings: 2},
+		{Previous: at(2, 10, 30), End: at(3, 9, 0), DayReset: true, Siblings: 1},
+	}
+	if _, err := b.Report("2020-03-02", "2020-03-03", "json", ReportOptions{RawDurations: true}); err != nil {
+		t.Fatal(err)
+	}
+	r := b.LastReport()
+	if len(r.Entries) != len(want) {
+		t.Fatalf("Backend.Report() has %d entries, want %d", len(r.Entries), len(want))
+	}
+	for i, w := range want {
+		got := r.Entries[i].Raw
+		if got == nil || !got.Previous.Equal(w.Previous) || !got.End.Equal(w.End) || got.Duration != w.Duration || got.DayReset != w.DayReset || got.Siblings != w.Siblings {
+			t.Errorf("entry %s raw = %+v, want %+v", r.Entries[i].ID, got, w)
+		}
+	}
+}
+
+func TestBackend_Report_excludeIDs(t *testing.T) {
+	data := `[[entries]]
+  id = "6fa459ea-ee8a-3ca4-894e-db77e160355e"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "1b4e28ba-2fa1-11d2-883f-0016d3cca427"
+  end = 2020-03-02T10:00:00Z
+  task = "api @acme"
+[[entries]]
+  id = "9c5b94b1-35ad-49bb-b118-8e8fc24abf80"
+  end = 2020-03-02T12:00:00Z
+  task = "mis-tagged @acme"
+[[entries]]
+  id = "a3bb189e-8bf9-3888-9912-ace4e6543002"
+  end = 2020-03-02T12:30:00Z
+  task = "docs @omw"
+`
+	tests := []struct {
+		name    string
+		opts    ReportOptions
+		entries int
+		task    time.Duration
+	}{
+		{"none", ReportOptions{}, 4, 3*time.Hour + 30*time.Minute},
+		{"full ID", ReportOptions{ExcludeIDs: []string{"9c5b94b1-35ad-49bb-b118-8e8fc24abf80"}}, 3, 90 * time.Minute},
+		{"short IDs", ReportOptions{ExcludeIDs: []string{"9c5b94b1", "a3bb189e"}}, 2, time.Hour},
+		{"with project", ReportOptions{Projects: []string{"acme"}, ExcludeIDs: []string{"9c5b94b1"}}, 1, time.Hour},
+		{"prefix too short", ReportOptions{ExcludeIDs: []string{"9c5b"}}, 4, 3*time.Hour + 30*time.Minute},
+	}
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := b.Report("2020-03-02", "2020-03-02", "json", tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			r := b.LastReport()
+			if len(r.Entries) != tt.entries || r.TaskHrs != tt.task {
+				t.Errorf("Backend.Report() = %d entries, %s task hours, want %d, %s", len(r.Entries), r.TaskHrs, tt.entries, tt.task)
+			}
+		})
+	}
+}
+
+func TestBackend_Report_minDuration(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T10:00:00Z
+  task = "api"
+[[entries]]
+  id = "3"
+  end = 2020-03-02T10:01:00Z
+  task = "api"
+[[entries]]
+  id = "4"
+  end = 2020-03-02T10:05:00Z
+  task = "coffee **"
+[[entries]]
+  id = "5"
+  end = 2020-03-02T10:35:00Z
+  task = "review"
+`
+	tests := []struct {
+		name    string
+		min     time.Duration
+		wantIDs string
+		task    time.Duration
+		brk     time.Duration
+	}{
+		{"none", 0, "12345", 91 * time.Minute, 4 * time.Minute},
+		{"double add", 2 * time.Minute, "245", 90 * time.Minute, 4 * time.Minute},
+		{"short break", 5 * time.Minute, "25", 90 * time.Minute, 0},
+	}
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := b.Report("2020-03-02", "2020-03-02", "json", ReportOptions{MinDuration: tt.min}); err != nil {
+				t.Fatal(err)
+			}
+			r := b.LastReport()
+			ids := ""
+			for _, e := range r.Entries {
+				ids += e.ID
+			}
+			if ids != tt.wantIDs || r.TaskHrs != tt.task || r.BrkHrs != tt.brk {
+				t.Errorf("Backend.Report() = entries %s, %s task, %s break, want %s, %s, %s", ids, r.TaskHrs, r.BrkHrs, tt.wantIDs, tt.task, tt.brk)
+			}
+		})
+	}
+}
+
+func TestBackend_Report_email(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T10:30:00Z
+  task = "api"
+`
+	tests := []struct {
+		name   string
+		email  Email
+		to     string
+		prefix string
+		suffix string
+	}{
+		{"defaults", Email{}, "2020-03-02",
+			"Subject: Time report 2020-03-02 (1h30m0s)\n\nHi,\n\nHere is my time report for 2020-03-02.\n\n",
+			"\n\nBest regards,\n"},
+		{"configured", Email{To: "boss@example.com", Name: "Sam", Greeting: "Hello team,"}, "2020-03-06",
+			"To: boss@example.com\nSubject: Time report 2020-03-02 to 2020-03-06 (1h30m0s)\n\nHello team,\n\nHere is my time report for 2020-03-02 to 2020-03-06.\n\n",
+			"\n\nBest regards,\nSam\n"},
+	}
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := b.Settings()
+			settings.Email = tt.email
+			b.Configure(settings)
+			out, err := b.Report("2020-03-02", tt.to, "text", ReportOptions{Email: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(out, tt.prefix) || !strings.HasSuffix(out, tt.suffix) || !strings.Contains(out, "-- api") {
+				t.Errorf("Backend.Report() email = %q, want it to start with %q, end with %q and include the report", out, tt.prefix, tt.suffix)
+			}
+			out, err = b.Report("2020-03-02", tt.to, "json", ReportOptions{Email: true})
+			if err != nil || strings.Contains(out, "Subject:") {
+				t.Errorf("Backend.Report() wrapped JSON output in an email: %q, %v", out, err)
+			}
+		})
+	}
+}
+
+func TestBackend_Report_displayZone(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T10:30:00Z
+  task = "api"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	zone := time.FixedZone("UTC-5", -5*60*60)
+	out, err := b.Report("2020-03-02", "2020-03-02", "text", ReportOptions{DisplayZone: zone})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Times In: UTC-5") {
+		t.Errorf("Backend.Report() doesn't name the display timezone: %q", out)
+	}
+	r := b.LastReport()
+	if len(r.Entries) != 2 || r.Timezone != "UTC-5" || r.TaskHrs != 90*time.Minute {
+		t.Fatalf("Backend.Report() = %d entries in %q with %s task hours, want 2 in UTC-5 with 1h30m0s", len(r.Entries), r.Timezone, r.TaskHrs)
+	}
+	api := r.Entries[1]
+	if api.Start.Location() != zone || api.Start.Hour() != 4 || api.Ts.Hour() != 5 || api.Ts.Minute() != 30 || api.Duration != 90*time.Minute {
+		t.Errorf("Backend.Report() api entry = %s to %s (%s), want 04:00 to 05:30 in UTC-5", api.Start, api.Ts, api.Duration)
+	}
+}
+
+func TestBackend_Report_includeEmptyDays(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-06T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-06T11:00:00Z
+  task = "api"
+[[entries]]
+  id = "3"
+  end = 2020-03-09T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "4"
+  end = 2020-03-09T09:45:00Z
+  task = "docs"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	out, err := b.Report("2020-03-06", "2020-03-09", "text", ReportOptions{IncludeEmptyDays: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		line string
+		task time.Duration
+	}{
+		{"Friday     2020-03-06 2h0m0s", 2 * time.Hour},
+		{"Saturday   2020-03-07 0s", 0},
+		{"Sunday     2020-03-08 0s", 0},
+		{"Monday     2020-03-09 45m0s", 45 * time.Minute},
+	}
+	days := b.LastReport().AllDays
+	if len(days) != len(want) {
+		t.Fatalf("Backend.Report() lists %d days, want %d", len(days), len(want))
+	}
+	for i, w := range want {
+		if days[i].TaskHrs != w.task {
+			t.Errorf("day %s = %s, want %s", days[i].Date.Format("2006-01-02"), days[i].TaskHrs, w.task)
+		}
+		if !strings.Contains(out, w.line+"\n") {
+			t.Errorf("Backend.Report() is missing %q: %q", w.line, out)
+		}
+	}
+}
+
+func TestBackend_Report_input(t *testing.T) {
+	own := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T10:00:00Z
+  task = "own work"
+`
+	archive := `[[entries]]
+  id = "a"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "b"
+  end = 2020-03-02T11:30:00Z
+  task = "archived work"
+`
+	tests := []struct {
+		name    string
+		input   string
+		task    time.Duration
 		wantErr bool
 	}{
-		// TODO: Add test cases.
+		{"archive", archive, 150 * time.Minute, false},
+		{"empty", "", 0, false},
+		{"invalid TOML", "[[entries]\n  id = ", 0, true},
 	}
+	b, cleanup := newTestBackend(t, own)
+	defer cleanup()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			b := &Backend{
-				ctx:    tt.fields.ctx,
-				config: tt.fields.config,
-				fp:     tt.fields.fp,
-				worker: tt.fields.worker,
+			_, err := b.Report("2020-03-02", "2020-03-02", "json", ReportOptions{Input: strings.NewReader(tt.input)})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Backend.Report() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			r := b.LastReport()
+			for _, e := range r.Entries {
+				if e.Title == "own work" {
+					t.Errorf("Backend.Report() of stdin included the configured timesheet")
+				}
+			}
+			if r.TaskHrs != tt.task {
+				t.Errorf("Backend.Report() task hours = %s, want %s", r.TaskHrs, tt.task)
+			}
+		})
+	}
+	got, err := ioutil.ReadFile(b.config.omwFile)
+	if err != nil || string(got) != own {
+		t.Errorf("Backend.Report() of stdin changed the timesheet: %q, %v", got, err)
+	}
+}
+
+func TestBackend_Report_showSource(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T10:00:00Z
+  task = "api"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	tests := []struct {
+		name string
+		opts ReportOptions
+		want string
+	}{
+		{"hidden", ReportOptions{}, ""},
+		{"own timesheet", ReportOptions{ShowSource: true}, b.config.omwFile},
+		{"stdin", ReportOptions{ShowSource: true, Input: strings.NewReader(data), InputName: "-"}, "-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := b.Report("2020-03-02", "2020-03-02", "text", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range b.LastReport().Entries {
+				if e.Source != tt.want {
+					t.Errorf("entry %s source = %q, want %q", e.ID, e.Source, tt.want)
+				}
 			}
-			if err := b.Stretch(); (err != nil) != tt.wantErr {
-				t.Errorf("Backend.Stretch() error = %v, wantErr %v", err, tt.wantErr)
+			if shown := strings.Contains(out, "-- api <"+tt.want+">"); shown != (tt.want != "") {
+				t.Errorf("Backend.Report() shows the source = %v: %q", shown, out)
+			}
+		})
+	}
+}
+
+func TestBackend_Report_limit(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T10:00:00Z
+  task = "api"
+[[entries]]
+  id = "3"
+  end = 2020-03-02T10:15:00Z
+  task = "coffee **"
+[[entries]]
+  id = "4"
+  end = 2020-03-02T12:00:00Z
+  task = "review"
+`
+	tests := []struct {
+		name    string
+		opts    ReportOptions
+		wantIDs string
+		total   int
+	}{
+		{"no limit", ReportOptions{}, "1234", 0},
+		{"first", ReportOptions{Limit: 2}, "12", 4},
+		{"tail", ReportOptions{Limit: 2, Tail: true}, "34", 4},
+		{"after sorting", ReportOptions{Limit: 1, Sort: "-duration"}, "4", 4},
+		{"limit above count", ReportOptions{Limit: 10}, "1234", 0},
+	}
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := b.Report("2020-03-02", "2020-03-02", "text", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := b.LastReport()
+			ids := ""
+			for _, e := range r.Entries {
+				ids += e.ID
+			}
+			if ids != tt.wantIDs || r.TotalEntries != tt.total {
+				t.Errorf("Backend.Report() = entries %s of %d, want %s of %d", ids, r.TotalEntries, tt.wantIDs, tt.total)
+			}
+			// the totals still cover every entry
+			if r.TaskHrs != 165*time.Minute || r.BrkHrs != 15*time.Minute {
+				t.Errorf("Backend.Report() totals = %s task, %s break, want 2h45m0s, 15m0s", r.TaskHrs, r.BrkHrs)
+			}
+			if shown := strings.Contains(out, "Showing "); shown != (tt.total > 0) {
+				t.Errorf("Backend.Report() says it is limited = %v: %q", shown, out)
+			}
+		})
+	}
+}
+
+func TestBackend_Report_round(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T09:07:30Z
+  task = "triage"
+[[entries]]
+  id = "3"
+  end = 2020-03-02T09:14:30Z
+  task = "email"
+[[entries]]
+  id = "4"
+  end = 2020-03-02T10:06:30Z
+  task = "review"
+[[entries]]
+  id = "5"
+  end = 2020-03-02T10:14:29Z
+  task = "coffee **"
+`
+	tests := []struct {
+		name  string
+		round time.Duration
+		task  time.Duration
+		brk   time.Duration
+	}{
+		{"exact", 0, 66*time.Minute + 30*time.Second, 7*time.Minute + 59*time.Second},
+		{"half up", 15 * time.Minute, time.Hour, 15 * time.Minute},
+		{"minutes", time.Minute, 67 * time.Minute, 8 * time.Minute},
+	}
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := b.Report("2020-03-02", "2020-03-02", "json", ReportOptions{Round: tt.round}); err != nil {
+				t.Fatal(err)
+			}
+			r := b.LastReport()
+			if r.TaskHrs != tt.task || r.BrkHrs != tt.brk {
+				t.Errorf("Backend.Report() rounded to %s = %s task, %s break, want %s, %s", tt.round, r.TaskHrs, r.BrkHrs, tt.task, tt.brk)
+			}
+		})
+	}
+}
+
+func TestBackend_Report_fillGaps(t *testing.T) {
+	defer localUTC()()
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T11:00:00Z
+  task = "api !acme"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	settings := b.Settings()
+	settings.DayStart = 9 * time.Hour
+	settings.DayEnd = 17 * time.Hour
+	settings.Billable = true
+	settings.Consultant.Rate = 100
+	b.Configure(settings)
+
+	opts := ReportOptions{FillGaps: true, AccountFor: 8 * time.Hour, AccountTolerance: DefaultAccountTolerance}
+	if _, err := b.Report("2020-03-02", "2020-03-02", "json", opts); err != nil {
+		t.Fatal(err)
+	}
+	r := b.LastReport()
+	if r.UnaccountedHrs != 6*time.Hour {
+		t.Errorf("Backend.Report() unaccounted = %s, want 6h0m0s", r.UnaccountedHrs)
+	}
+	if acc := r.Accounting; acc.Failed != 1 || acc.Days[0].Worked != 2*time.Hour {
+		t.Errorf("Backend.Report() accounted %s with %d failed, want 2h0m0s and 1 failed - gaps aren't work", acc.Days[0].Worked, acc.Failed)
+	}
+
+	opts = ReportOptions{FillGaps: true, Invoice: "acme"}
+	if _, err := b.Report("2020-03-02", "2020-03-02", "json", opts); err != nil {
+		t.Fatal(err)
+	}
+	if inv := b.LastReport().Invoice; inv.Hours != 2 || inv.Total != 200 {
+		t.Errorf("Backend.Report() invoiced %.2f hours for %.2f, want 2 hours for 200 - gaps aren't billable", inv.Hours, inv.Total)
+	}
+}
+
+func TestBackend_Report_fillGaps_window(t *testing.T) {
+	defer localUTC()()
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T00:30:00Z
+  task = "late hotfix"
+[[entries]]
+  id = "2"
+  end = 2020-03-03T07:00:00Z
+  task = "hello"
+[[entries]]
+  id = "3"
+  end = 2020-03-03T10:00:00Z
+  task = "api"
+[[entries]]
+  id = "4"
+  end = 2020-03-04T18:00:00Z
+  task = "hello"
+[[entries]]
+  id = "5"
+  end = 2020-03-04T19:00:00Z
+  task = "evening api"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	settings := b.Settings()
+	settings.DayStart = 9 * time.Hour
+	settings.DayEnd = 17 * time.Hour
+	b.Configure(settings)
+	if _, err := b.Report("2020-03-02", "2020-03-04", "json", ReportOptions{FillGaps: true}); err != nil {
+		t.Fatal(err)
+	}
+	// the gaps never reach outside 09:00 to 17:00
+	want := []string{"2020-03-02 09:00-17:00", "2020-03-03 10:00-17:00", "2020-03-04 09:00-17:00"}
+	got := []string{}
+	for _, e := range b.LastReport().Entries {
+		if e.Unaccounted {
+			got = append(got, e.Start.Format("2006-01-02 15:04")+"-"+e.Ts.Format("15:04"))
+		}
+	}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("Backend.Report() gaps = %v, want %v", got, want)
+	}
+	if r := b.LastReport(); r.UnaccountedHrs != 23*time.Hour {
+		t.Errorf("Backend.Report() unaccounted = %s, want 23h0m0s", r.UnaccountedHrs)
+	}
+}
+
+func TestBackend_Report_breakPolicy(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T11:00:00Z
+  task = "api !acme"
+[[entries]]
+  id = "3"
+  end = 2020-03-02T11:12:00Z
+  task = "coffee !acme **"
+[[entries]]
+  id = "4"
+  end = 2020-03-02T12:00:00Z
+  task = "lunch !acme **"
+[[entries]]
+  id = "5"
+  end = 2020-03-02T13:00:00Z
+  task = "api !acme"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	settings := b.Settings()
+	settings.Billable = true
+	settings.Consultant.Rate = 100
+	settings.PaidBreakLimit = 15 * time.Minute
+	settings.ExpectedHours = 3 * time.Hour
+	b.Configure(settings)
+	opts := ReportOptions{
+		BreakPolicy:      true,
+		Invoice:          "acme",
+		AccountFor:       4 * time.Hour,
+		AnnotateOvertime: true,
+	}
+	if _, err := b.Report("2020-03-02", "2020-03-02", "json", opts); err != nil {
+		t.Fatal(err)
+	}
+	r := b.LastReport()
+	paid := 3*time.Hour + 12*time.Minute
+	if r.TaskHrs != paid || r.BillableHrs != paid || r.BrkHrs != time.Hour || r.PaidBrkHrs != 12*time.Minute {
+		t.Errorf("Backend.Report() = %s task, %s billable, %s break, %s paid, want %s, %s, 1h0m0s, 12m0s",
+			r.TaskHrs, r.BillableHrs, r.BrkHrs, r.PaidBrkHrs, paid, paid)
+	}
+	if inv := r.Invoice; inv.Hours != 3.2 || inv.Total != 320 {
+		t.Errorf("Backend.Report() invoiced %.2f hours for %.2f, want 3.20 hours for 320 like the billable hours", inv.Hours, inv.Total)
+	}
+	if worked := r.Accounting.Days[0].Worked; worked != 4*time.Hour {
+		t.Errorf("Backend.Report() accounted %s, want 4h0m0s with the paid break counted once", worked)
+	}
+	if day := r.OvertimeDays[0]; day.TaskHrs != paid || day.BrkHrs != 48*time.Minute || r.OvertimeHrs != 12*time.Minute {
+		t.Errorf("Backend.Report() day = %s task, %s break, %s overtime, want %s, 48m0s, 12m0s", day.TaskHrs, day.BrkHrs, r.OvertimeHrs, paid)
+	}
+}
+
+func TestBackend_Report_excludeWeekendBreaks(t *testing.T) {
+	defer localUTC()()
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T17:00:00Z
+  task = "api"
+[[entries]]
+  id = "3"
+  end = 2020-03-02T18:00:00Z
+  task = "dinner **"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	settings := b.Settings()
+	settings.DayStart = 9 * time.Hour
+	settings.DayEnd = 17 * time.Hour
+	b.Configure(settings)
+	opts := ReportOptions{ExcludeWeekendBreaks: true, AccountFor: 8 * time.Hour}
+	if _, err := b.Report("2020-03-02", "2020-03-02", "json", opts); err != nil {
+		t.Fatal(err)
+	}
+	r := b.LastReport()
+	if r.BrkHrs != 0 || len(r.Entries) != 3 {
+		t.Errorf("Backend.Report() = %s break in %d entries, want 0s in 3", r.BrkHrs, len(r.Entries))
+	}
+	if day := r.Accounting.Days[0]; day.Worked != 8*time.Hour || !day.Pass {
+		t.Errorf("Backend.Report() accounted %s, want 8h0m0s without the evening break", day.Worked)
+	}
+}
+
+func TestBackend_Report_accuracyCheck(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T09:00:20Z
+  task = "oops"
+[[entries]]
+  id = "3"
+  end = 2020-03-02T10:00:00Z
+  task = "api"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	settings := b.Settings()
+	settings.MinDuration = time.Minute
+	settings.MaxDuration = 4 * time.Hour
+	b.Configure(settings)
+	got, err := b.Report("2020-03-02", "2020-03-02", "text", ReportOptions{AccuracyCheck: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(got, "Accuracy Check: 2020-03-02 to 2020-03-02\n") || !strings.Contains(got, "oops") || strings.Contains(got, "api") {
+		t.Errorf("Backend.Report() = %q, want the period ending on the last day and only oops", got)
+	}
+}
+
+func TestBackend_buildInvoice_client(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T11:00:00Z
+  task = "api !AcmeCorp"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	settings := b.Settings()
+	settings.Consultant.Rate = 100
+	yes := true
+	settings.Clients = map[string]Client{"acmecorp": {Party: Party{Name: "Acme Corp", Rate: 150}, Billable: &yes}}
+	b.Configure(settings)
+	if _, err := b.Report("2020-03-02", "2020-03-02", "json", ReportOptions{Invoice: "AcmeCorp"}); err != nil {
+		t.Fatal(err)
+	}
+	inv := b.LastReport().Invoice
+	if inv.Client.Name != "Acme Corp" || inv.Hours != 2 || inv.Total != 300 {
+		t.Errorf("Backend.Report() invoiced %q %.2f hours for %.2f, want Acme Corp 2 hours for 300", inv.Client.Name, inv.Hours, inv.Total)
+	}
+}
+
+func TestBackend_Report_empty(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+`
+	message := "No entries found between 2020-03-03 and 2020-03-04\n"
+	tests := []struct {
+		name string
+		opts ReportOptions
+		want string
+	}{
+		{"message", ReportOptions{}, message},
+		{"empty days", ReportOptions{IncludeEmptyDays: true}, "2020-03-04 0s"},
+		{"running balance", ReportOptions{RunningBalance: true}, "Final Balance:"},
+		{"calendar", ReportOptions{Calendar: true}, "Task Hours by Day"},
+		{"account for", ReportOptions{AccountFor: 8 * time.Hour}, "FAIL: 2 of 2 days"},
+	}
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := b.Report("2020-03-03", "2020-03-04", "text", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(got, tt.want) || (tt.want != message && got == message) {
+				t.Errorf("Backend.Report() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackend_MigrateTZ(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00+02:00
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T08:30:00Z
+  task = "write docs @omw"
+[[entries]]
+  id = "3"
+  end = 2020-03-02T05:45:00-05:00
+  task = "lunch **"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	want, err := b.Report("2020-03-01", "2020-03-03", "text", ReportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, loc := range []*time.Location{time.UTC, time.FixedZone("UTC-7", -7*60*60), time.Local} {
+		if _, err := b.MigrateTZ(loc); err != nil {
+			t.Fatalf("Backend.MigrateTZ(%s) error = %v", loc, err)
+		}
+		saved, _ := readTimesheet(b.config.omwFile)
+		for _, e := range saved.Entries {
+			_, got := e.End.Zone()
+			_, want := e.End.In(loc).Zone()
+			if got != want {
+				t.Errorf("Backend.MigrateTZ(%s) left %s", loc, e.End)
+			}
+		}
+		got, err := b.Report("2020-03-01", "2020-03-03", "text", ReportOptions{})
+		if err != nil || got != want {
+			t.Errorf("report after Backend.MigrateTZ(%s) = %s, %v, want %s", loc, got, err, want)
+		}
+	}
+}
+
+func TestBackend_Report_modifiedSince(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T10:00:00Z
+  task = "api"
+  modified = 2020-03-02T10:00:00Z
+[[entries]]
+  id = "3"
+  end = 2020-03-02T11:00:00Z
+  task = "review"
+  modified = 2020-03-05T08:00:00Z
+`
+	since := time.Date(2020, 3, 4, 0, 0, 0, 0, time.UTC)
+	titles := func(b *Backend) []string {
+		if _, err := b.Report("2020-03-02", "2020-03-02", "json", ReportOptions{ModifiedSince: since}); err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, entry := range b.LastReport().Entries {
+			got = append(got, entry.Title)
+		}
+		return got
+	}
+	tests := []struct {
+		name   string
+		change func(b *Backend) error
+		want   []string
+	}{
+		{"unchanged", func(b *Backend) error { return nil }, []string{"review"}},
+		{"set break", func(b *Backend) error { _, err := b.SetBreak("2", true); return err }, []string{"api", "review"}},
+		{"set billable", func(b *Backend) error { _, err := b.SetBillable("2", true); return err }, []string{"api", "review"}},
+		{"reid", func(b *Backend) error { _, err := b.Reid(); return err }, []string{"hello", "api", "review"}},
+		{"migrate tz", func(b *Backend) error { _, err := b.MigrateTZ(time.FixedZone("UTC+2", 2*60*60)); return err }, []string{"hello", "api", "review"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, cleanup := newTestBackend(t, data)
+			defer cleanup()
+			if err := tt.change(b); err != nil {
+				t.Fatal(err)
+			}
+			if got := titles(b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Backend.Report() modified since %s = %v, want %v", since.Format("2006-01-02"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackend_Stretch(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"empty file", "", true},
+		{"no entries", "entries = []\n", true},
+		{"one entry", `[[entries]]
+  id = "a1"
+  task = "hello"
+  end = 2020-07-06T09:00:00Z
+`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, cleanup := newTestBackend(t, tt.data)
+			defer cleanup()
+			entry, _, err := b.Stretch()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Backend.Stretch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && entry.Task != "hello" {
+				t.Errorf("Backend.Stretch() task = %q, want %q", entry.Task, "hello")
 			}
 		})
 	}
@@ -253,3 +1583,497 @@ func Test_processOutput(t *testing.T) {
 		})
 	}
 }
+
+func TestBackend_Add_modifierOnly(t *testing.T) {
+	b, cleanup := newTestBackend(t, "")
+	defer cleanup()
+	for _, task := range []string{"**", "***", "**:lunch", "@acme $"} {
+		if _, err := b.Add([]string{task}); Code(err) != CodeParse {
+			t.Errorf("Backend.Add(%q) error = %v, want a %s error", task, err, CodeParse)
+		}
+	}
+	if _, err := b.Add([]string{"lunch **"}); err != nil {
+		t.Errorf("Backend.Add(%q) error = %v", "lunch **", err)
+	}
+}
+
+func TestBackend_Add_tabs(t *testing.T) {
+	b, cleanup := newTestBackend(t, "")
+	defer cleanup()
+	b.Add([]string{"hello"})
+	saved, err := b.Add([]string{"fix\tlogin\t\tbug\n@acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "fix login bug @acme"
+	if saved[0].Task != want {
+		t.Errorf("Backend.Add() saved %q, want %q", saved[0].Task, want)
+	}
+	day := time.Now().Format("2006-01-02")
+	check := func(step string) {
+		t.Helper()
+		data, err := readTimesheet(b.config.omwFile)
+		if err != nil || len(data.Entries) != 2 || data.Entries[1].Task != want {
+			t.Fatalf("after %s the timesheet holds %+v, %v, want %q", step, data, err, want)
+		}
+		if _, err := b.Report(day, day, "json", ReportOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		entries := b.LastReport().Entries
+		if got := entries[len(entries)-1]; got.Title != "fix login bug" || got.Project != "acme" {
+			t.Errorf("after %s the report shows %q @%s, want fix login bug @acme", step, got.Title, got.Project)
+		}
+	}
+	check("add")
+
+	os.Unsetenv("OMW_TERM")
+	defer os.Setenv("EDITOR", os.Getenv("EDITOR"))
+	os.Setenv("EDITOR", "true")
+	if _, err := b.Edit(); err != nil {
+		t.Fatal(err)
+	}
+	check("edit")
+}
+
+func TestBackend_parseEntry(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name string
+		s    string
+		want *ReportEntry
+	}{
+		{"plain task", "write report", &ReportEntry{Title: "write report"}},
+		{"break", "lunch **", &ReportEntry{Title: "lunch", Brk: true}},
+		{"ignore", "commuting ***", &ReportEntry{Title: "commuting", Ignore: true}},
+		{"project", "fix login @acme", &ReportEntry{Title: "fix login", Project: "acme"}},
+		{"billable", "fix login $ @acme", &ReportEntry{Title: "fix login", Project: "acme", Billable: &yes}},
+		{"non-billable break", "coffee $0 **", &ReportEntry{Title: "coffee", Brk: true, Billable: &no}},
+		{"tags", "standup #meeting #daily @acme", &ReportEntry{Title: "standup", Project: "acme", Tags: []string{"meeting", "daily"}}},
+		{"tags only", "#standup", &ReportEntry{Title: "standup", Tags: []string{"standup"}}},
+		{"tags only break", "#coffee #social **", &ReportEntry{Title: "coffee social", Brk: true, Tags: []string{"coffee", "social"}}},
+		{"estimate", "write migration est:1h30m @acme", &ReportEntry{Title: "write migration", Project: "acme", Estimate: 90 * time.Minute}},
+		{"invalid estimate", "est:soon", &ReportEntry{Title: "est:soon"}},
+		{"break category", "team sync **:meeting", &ReportEntry{Title: "team sync", Brk: true, BrkCategory: "meeting"}},
+		{"accented break", "déjeuner **", &ReportEntry{Title: "déjeuner", Brk: true}},
+		{"CJK", "会议 @acme", &ReportEntry{Title: "会议", Project: "acme"}},
+		{"break only", "**", &ReportEntry{Brk: true}},
+		{"ignore only", "***", &ReportEntry{Ignore: true}},
+		{"break category only", "**:lunch", &ReportEntry{Brk: true, BrkCategory: "lunch"}},
+	}
+	b := &Backend{config: &config{}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := b.parseEntry(tt.s)
+			if err != nil {
+				t.Fatalf("Backend.parseEntry() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Backend.parseEntry() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackend_isBillable(t *testing.T) {
+	no := false
+	b := &Backend{config: &config{settings: Settings{
+		Billable:         true,
+		BillableProjects: map[string]bool{"acmecorp": false, "acmecorp/support": true},
+		Clients:          map[string]Client{"globex": {Billable: &no}},
+	}}}
+	tests := []struct {
+		task string
+		want bool
+	}{
+		{"api", true},
+		{"api @AcmeCorp", false},
+		{"api @acmecorp", false},
+		{"tickets @AcmeCorp/Support", true},
+		{"api $ @AcmeCorp", true},
+		{"review !Globex", false},
+		{"review !globex", false},
+		{"review @internal !Globex $", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.task, func(t *testing.T) {
+			entry, err := b.parseEntry(tt.task)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := b.isBillable(entry); got != tt.want {
+				t.Errorf("Backend.isBillable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackend_decorateTask(t *testing.T) {
+	tests := []struct {
+		name string
+		task string
+		want string
+	}{
+		{"plain task", "write report", "me: write report -dm"},
+		{"break marker stays last", "lunch **", "me: lunch -dm **"},
+		{"ignore marker stays last", "commuting ***", "me: commuting -dm ***"},
+		{"already decorated", "me: write report -dm @acme", "me: write report -dm @acme"},
+	}
+	b := &Backend{config: &config{settings: Settings{TaskPrefix: "me:", TaskSuffix: "-dm"}}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.decorateTask(tt.task); got != tt.want {
+				t.Errorf("Backend.decorateTask() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackend_SetBreak(t *testing.T) {
+	data := `
+[[entries]]
+  id = "1"
+  end = 2019-12-16T09:00:00Z
+  task = "coffee @acme"
+[[entries]]
+  id = "2"
+  end = 2019-12-16T10:00:00Z
+  task = "commuting ***"
+[[entries]]
+  id = "3"
+  end = 2019-12-16T11:00:00Z
+  task = "sync **:meeting"
+[[entries]]
+  id = "5c1f9a7e-0c2d-4d0e-9a51-6f0e8d7c2b11"
+  end = 2019-12-16T12:00:00Z
+  task = "lunch"
+[[entries]]
+  id = "8e2b4c1d-1111-4d0e-9a51-6f0e8d7c2b11"
+  end = 2019-12-16T13:00:00Z
+  task = "api"
+[[entries]]
+  id = "8e2b4c1d-2222-4d0e-9a51-6f0e8d7c2b11"
+  end = 2019-12-16T14:00:00Z
+  task = "docs"
+`
+	tests := []struct {
+		name    string
+		id      string
+		brk     bool
+		want    string
+		wantErr bool
+	}{
+		{"mark break", "1", true, "coffee @acme **", false},
+		{"unmark break", "1", false, "coffee @acme", false},
+		{"replace ignore", "2", true, "commuting **", false},
+		{"keep category", "3", true, "sync **:meeting", false},
+		{"unmark category", "3", false, "sync", false},
+		{"missing id", "4", true, "", true},
+		{"short id", "5c1f9a7e", true, "lunch **", false},
+		{"ambiguous short id", "8e2b4c1d", true, "", true},
+	}
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := b.SetBreak(tt.id, tt.brk)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Backend.SetBreak() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Task != tt.want {
+				t.Errorf("Backend.SetBreak() task = %q, want %q", got.Task, tt.want)
+			}
+			saved, err := b.readEntries()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range saved.Entries {
+				if e.ID == got.ID && e.Task != tt.want {
+					t.Errorf("saved task = %q, want %q", e.Task, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBackend_Delete(t *testing.T) {
+	data := `
+[[entries]]
+  id = "0e8d3b2a-1111"
+  end = 2019-12-16T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "0e8d3b2a-2222"
+  end = 2019-12-16T10:00:00Z
+  task = "fix login"
+[[entries]]
+  id = "5c1f9a7e-3333"
+  end = 2019-12-16T11:00:00Z
+  task = "write docs"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	if _, err := b.Delete("missing", false); Code(err) != CodeNotFound {
+		t.Errorf("Backend.Delete() of a missing ID error = %v, want %s", err, CodeNotFound)
+	}
+	if _, err := b.Delete("0e8d3b2a", false); Code(err) != CodeParse {
+		t.Errorf("Backend.Delete() of an ambiguous short ID error = %v, want %s", err, CodeParse)
+	}
+	deleted, err := b.Delete("5c1f9a7e", false)
+	if err != nil || deleted.Task != "write docs" {
+		t.Fatalf("Backend.Delete() = %+v, %v, want write docs", deleted, err)
+	}
+	if _, err := b.Delete("0e8d3b2a-2222", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Delete("0e8d3b2a-1111", false); err == nil {
+		t.Error("Backend.Delete() of the only entry without force did not fail")
+	}
+	if _, err := b.Delete("0e8d3b2a-1111", true); err != nil {
+		t.Errorf("Backend.Delete() of the only entry with force error = %v", err)
+	}
+	saved, err := readTimesheet(b.config.omwFile)
+	if err != nil || len(saved.Entries) != 0 {
+		t.Errorf("timesheet after deleting every entry = %+v, %v", saved, err)
+	}
+	if _, err := os.Stat(b.config.omwFile + ".bak"); err != nil {
+		t.Errorf("Backend.Delete() didn't keep a backup: %v", err)
+	}
+}
+
+func TestBackend_SetBillable(t *testing.T) {
+	data := `
+[[entries]]
+  id = "0e8d3b2a-1111"
+  end = 2019-12-16T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "5c1f9a7e-2222"
+  end = 2019-12-16T10:00:00Z
+  task = "fix login @acme $"
+[[entries]]
+  id = "3"
+  end = 2019-12-16T11:00:00Z
+  task = "sync **"
+`
+	tests := []struct {
+		name     string
+		id       string
+		billable bool
+		want     string
+		wantErr  bool
+	}{
+		{"mark not billable", "5c1f9a7e-2222", false, "fix login @acme $0", false},
+		{"mark billable by short ID", "5c1f9a7e", true, "fix login @acme $", false},
+		{"keep break modifier last", "3", true, "sync $ **", false},
+		{"missing id", "4", true, "", true},
+	}
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := b.SetBillable(tt.id, tt.billable)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Backend.SetBillable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Task != tt.want {
+				t.Errorf("Backend.SetBillable() task = %q, want %q", got.Task, tt.want)
+			}
+		})
+	}
+	b.SetBillable("5c1f9a7e", false)
+	if _, err := b.Report("2019-12-16", "2019-12-16", "json", ReportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.LastReport().BillableHrs; got != 0 {
+		t.Errorf("billable hours after Backend.SetBillable(false) = %s, want 0s", got)
+	}
+}
+
+func TestBackend_Edit_editorFails(t *testing.T) {
+	tests := []struct {
+		name   string
+		editor string
+	}{
+		{"editor not found", "omw-no-such-editor"},
+		{"editor exits nonzero", "false"},
+	}
+	os.Unsetenv("OMW_TERM")
+	defer os.Setenv("EDITOR", os.Getenv("EDITOR"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, cleanup := newTestBackend(t, "")
+			defer cleanup()
+			os.Setenv("EDITOR", tt.editor)
+			if _, err := b.Edit(); err == nil {
+				t.Fatalf("Backend.Edit() with EDITOR=%s did not fail", tt.editor)
+			}
+			files, err := ioutil.ReadDir(b.config.omwDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, f := range files {
+				if f.Name() != "omw.toml" {
+					t.Errorf("Backend.Edit() left %s behind", f.Name())
+				}
+			}
+		})
+	}
+}
+
+func TestBackend_Edit_fileChanged(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	// an "editor" that changes the timesheet itself, as a cron job
+	// or file sync might while the real editor is open
+	editor := filepath.Join(b.config.omwDir, "editor.sh")
+	script := fmt.Sprintf("#!/bin/sh\necho '# added elsewhere' >> %s\n", b.config.omwFile)
+	if err := ioutil.WriteFile(editor, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.Unsetenv("OMW_TERM")
+	defer os.Setenv("EDITOR", os.Getenv("EDITOR"))
+	os.Setenv("EDITOR", editor)
+
+	_, err := b.Edit()
+	if err == nil || Code(err) != CodeLock {
+		t.Fatalf("Backend.Edit() error = %v, want a %s error", err, CodeLock)
+	}
+	got, err := ioutil.ReadFile(b.config.omwFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(got), "# added elsewhere\n") {
+		t.Errorf("Backend.Edit() overwrote the change made during the edit: %q", got)
+	}
+	kept, err := filepath.Glob(b.config.omwFile + ".edit-*")
+	if err != nil || len(kept) != 1 {
+		t.Errorf("Backend.Edit() kept %v, want one copy of the edit", kept)
+	}
+}
+
+func TestBackend_Reid(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "1"
+  end = 2020-03-02T10:00:00Z
+  task = "api @acme"
+[[entries]]
+  id = "3"
+  end = 2020-03-02T10:30:00Z
+  task = "coffee **"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	before, err := readTimesheet(b.config.omwFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed, err := b.Reid()
+	if err != nil || changed != 3 {
+		t.Fatalf("Backend.Reid() = %d, %v, want 3", changed, err)
+	}
+	after, err := readTimesheet(b.config.omwFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after.Entries) != len(before.Entries) {
+		t.Fatalf("Backend.Reid() left %d entries, want %d", len(after.Entries), len(before.Entries))
+	}
+	seen := map[string]bool{}
+	for i, e := range after.Entries {
+		old := before.Entries[i]
+		if e.ID == old.ID || seen[e.ID] {
+			t.Errorf("entry %d kept ID %q or reused it", i, e.ID)
+		}
+		seen[e.ID] = true
+		if e.Task != old.Task || !e.End.Equal(old.End) {
+			t.Errorf("entry %d = %q at %s, want %q at %s", i, e.Task, e.End, old.Task, old.End)
+		}
+	}
+	if _, err := os.Stat(b.config.omwFile + ".bak"); err != nil {
+		t.Errorf("Backend.Reid() didn't write a backup: %v", err)
+	}
+}
+
+func TestBackend_Edit_summary(t *testing.T) {
+	data := `[[entries]]
+  id = "1"
+  end = 2020-03-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-03-02T10:00:00Z
+  task = "api"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	if b.LastEdit() != nil {
+		t.Fatalf("Backend.LastEdit() before any edit = %+v, want nil", b.LastEdit())
+	}
+	// the "editor" renames a task and adds an entry that reuses an ID
+	edited := filepath.Join(b.config.omwDir, "edited.toml")
+	content := strings.Replace(data, `"api"`, `"api @acme"`, 1) + `[[entries]]
+  id = "2"
+  end = 2020-03-02T11:00:00Z
+  task = "review"
+`
+	if err := ioutil.WriteFile(edited, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	editor := filepath.Join(b.config.omwDir, "editor.sh")
+	script := fmt.Sprintf("#!/bin/sh\ncp %s \"$1\"\n", edited)
+	if err := ioutil.WriteFile(editor, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.Unsetenv("OMW_TERM")
+	defer os.Setenv("EDITOR", os.Getenv("EDITOR"))
+	os.Setenv("EDITOR", editor)
+
+	if _, err := b.Edit(); err != nil {
+		t.Fatal(err)
+	}
+	got := b.LastEdit()
+	want := EditSummary{Before: 2, After: 3, Changed: 2, FixedIDs: 1, Backup: b.config.omwFile + ".bak"}
+	if got == nil || *got != want {
+		t.Fatalf("Backend.LastEdit() = %+v, want %+v", got, want)
+	}
+	backup, err := ioutil.ReadFile(got.Backup)
+	if err != nil || string(backup) != data {
+		t.Errorf("backup %s = %q, %v, want the timesheet from before the edit", got.Backup, backup, err)
+	}
+}
+
+// BenchmarkBackend_Report_oneDay reports on the last day of a 10k entry
+// timesheet.  The entry cache is warm after the first run, so this
+// measures the report itself rather than reading the file.
+func BenchmarkBackend_Report_oneDay(b *testing.B) {
+	var data strings.Builder
+	start := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 10000; i++ {
+		end := start.AddDate(0, 0, i/10).Add(time.Duration(i%10) * 30 * time.Minute)
+		fmt.Fprintf(&data, "[[entries]]\n  id = \"%d\"\n  end = %s\n  task = \"task %d @project #tag\"\n", i, end.Format(time.RFC3339), i)
+	}
+	backend, cleanup := newTestBackend(b, data.String())
+	defer cleanup()
+	day := start.AddDate(0, 0, 999).Format("2006-01-02")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := backend.Report(day, day, "json", ReportOptions{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package backend
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultSplitSeparator divides a task into entries that share their time,
+// as in omw add "review || standup"
+const DefaultSplitSeparator = "||"
+
+// splitTask divides task on sep, dropping empty parts
+// An empty sep leaves the task whole.
+func splitTask(task, sep string) []string {
+	parts := []string{task}
+	if sep != "" {
+		parts = strings.Split(task, sep)
+	}
+	tasks := []string{}
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			tasks = append(tasks, part)
+		}
+	}
+	return tasks
+}
+
+// countSiblings counts the entries saved at each timestamp
+// Entries with a stored duration don't share the time before them.
+func countSiblings(entries []SavedEntry) map[int64]int {
+	counts := make(map[int64]int)
+	for _, e := range entries {
+		if e.Task != "" && e.Duration == 0 {
+			counts[e.End.UnixNano()]++
+		}
+	}
+	return counts
+}
+
+// siblingGroup divides the time from start to ts between the n entries
+// saved at ts
+// Each entry gets an equal share rounded down to the second, and the
+// first entry also gets the remainder, so the shares always add up to the
+// time between the entries.
+type siblingGroup struct {
+	ts    time.Time
+	start time.Time
+	n     int
+	i     int
+}
+
+// share returns the duration of the next entry of the group
+func (g *siblingGroup) share() time.Duration {
+	span := g.ts.Sub(g.start)
+	if g.n <= 1 {
+		return span
+	}
+	each := (span / time.Duration(g.n)).Truncate(time.Second)
+	g.i++
+	if g.i == 1 {
+		return span - each*time.Duration(g.n-1)
+	}
+	return each
+}
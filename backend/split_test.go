@@ -0,0 +1,65 @@
+package backend
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBackend_Report_splitTasks(t *testing.T) {
+	data := `
+[[entries]]
+  id = "1"
+  end = 2020-01-02T09:00:00Z
+  task = "hello"
+[[entries]]
+  id = "2"
+  end = 2020-01-02T10:00:01Z
+  task = "review"
+[[entries]]
+  id = "3"
+  end = 2020-01-02T10:00:01Z
+  task = "standup"
+[[entries]]
+  id = "4"
+  end = 2020-01-02T10:00:01Z
+  task = "email"
+[[entries]]
+  id = "5"
+  end = 2020-01-02T11:00:00Z
+  task = "deploy"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	output, err := b.Report("2020-01-02", "2020-01-02", "json", ReportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := Report{}
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatal(err)
+	}
+	got := []time.Duration{}
+	for _, entry := range report.Entries {
+		got = append(got, entry.Duration)
+	}
+	want := []time.Duration{0, 20*time.Minute + time.Second, 20 * time.Minute, 20 * time.Minute, 59*time.Minute + 59*time.Second}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("durations = %v, want %v", got, want)
+	}
+	if report.TaskHrs != 2*time.Hour {
+		t.Errorf("TaskHrs = %v, want 2h", report.TaskHrs)
+	}
+}
+
+func TestSplitTask(t *testing.T) {
+	got := splitTask(" review @acme || standup ** ||", DefaultSplitSeparator)
+	want := []string{"review @acme", "standup **"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitTask() = %q, want %q", got, want)
+	}
+	if got := splitTask("a || b", ""); !reflect.DeepEqual(got, []string{"a || b"}) {
+		t.Errorf("splitTask() without separator = %q", got)
+	}
+}
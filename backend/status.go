@@ -0,0 +1,160 @@
+package backend
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Status describes the most recent entry of the timesheet, the task that
+// is being tracked now
+type Status struct {
+	ID    string    `json:"id"`
+	Task  string    `json:"task"`
+	Since time.Time `json:"since"`
+}
+
+// Current returns the most recent entry parsed as a ReportEntry, running
+// from when it was added until now, or nil if the timesheet has no entries
+// or the most recent task is blank
+// It reads the timesheet rather than Status(), which skips blank tasks.
+func (b *Backend) Current() (*ReportEntry, error) {
+	data, err := b.readEntries()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var last *SavedEntry
+	for i := range data.Entries {
+		if e := &data.Entries[i]; last == nil || !e.End.Before(last.End) {
+			last = e
+		}
+	}
+	if last == nil || strings.TrimSpace(last.Task) == "" {
+		return nil, nil
+	}
+	entry, err := b.parseEntry(last.Task)
+	if err != nil {
+		return nil, withCode(CodeCorrupt, errors.Wrapf(err, "can't parse task %q", last.Task))
+	}
+	now := time.Now()
+	entry.ID = last.ID
+	entry.Ts = last.End
+	entry.Start = last.End
+	entry.End = now
+	entry.Duration = now.Sub(last.End)
+	return entry, nil
+}
+
+// Overdue reports whether the task has been running for longer than
+// limit at now, which suggests a forgotten switch
+// A zero limit never warns.
+func (s *Status) Overdue(now time.Time, limit time.Duration) bool {
+	return limit > 0 && now.Sub(s.Since) > limit
+}
+
+// statusFile is the sidecar that lets Status() skip parsing the timesheet
+// It records the size and modification time of the timesheet it was
+// written for, so any change to the timesheet, by omw or anything else,
+// makes it stale.
+type statusFile struct {
+	Status
+	ModTime time.Time `json:"modTime"`
+	Size    int64     `json:"size"`
+}
+
+// statusPath returns the sidecar kept next to timesheet
+func statusPath(timesheet string) string {
+	return timesheet + ".status"
+}
+
+// Status returns the most recent entry, read from the sidecar file when it
+// is up to date so a status bar can poll it cheaply
+// When the sidecar is missing or stale the timesheet is parsed and the
+// sidecar rewritten.
+func (b *Backend) Status() (*Status, error) {
+	info, err := os.Stat(b.config.omwFile)
+	if err != nil {
+		return nil, withCode(CodeNotFound, errors.Wrap(err, "can't read timesheet"))
+	}
+	if s := readStatus(statusPath(b.config.omwFile), info); s != nil {
+		return s, nil
+	}
+	data, err := b.readEntries()
+	if err != nil {
+		return nil, err
+	}
+	s := lastStatus(data.Entries)
+	writeStatus(statusPath(b.config.omwFile), s, info)
+	if s == nil {
+		return nil, withCode(CodeNotFound, errors.New("no entries in timesheet"))
+	}
+	return s, nil
+}
+
+// refreshStatus rewrites the sidecar after omw saves the timesheet
+func (b *Backend) refreshStatus(entries []SavedEntry) {
+	info, err := os.Stat(b.config.omwFile)
+	if err != nil {
+		return
+	}
+	writeStatus(statusPath(b.config.omwFile), lastStatus(entries), info)
+}
+
+// lastStatus returns the status of the entry with the latest timestamp,
+// whichever way entries are sorted, or nil if there are none
+func lastStatus(entries []SavedEntry) *Status {
+	var last *SavedEntry
+	for i := range entries {
+		e := &entries[i]
+		if e.Task == "" {
+			continue
+		}
+		if last == nil || !e.End.Before(last.End) {
+			last = e
+		}
+	}
+	if last == nil {
+		return nil
+	}
+	return &Status{ID: last.ID, Task: last.Task, Since: last.End}
+}
+
+// readStatus returns the status saved in the sidecar at path if it was
+// written for the timesheet described by info, or nil
+func readStatus(path string, info os.FileInfo) *Status {
+	r, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	s := statusFile{}
+	if json.Unmarshal(r, &s) != nil || s.ID == "" {
+		return nil
+	}
+	if !s.ModTime.Equal(info.ModTime()) || s.Size != info.Size() {
+		return nil
+	}
+	return &s.Status
+}
+
+// writeStatus saves s to the sidecar at path, or removes the sidecar if
+// there is no status
+// The sidecar is only an optimization, so failures are ignored and the
+// next Status() falls back to parsing the timesheet.
+func writeStatus(path string, s *Status, info os.FileInfo) {
+	if s == nil {
+		os.Remove(path)
+		return
+	}
+	r, err := json.Marshal(statusFile{*s, info.ModTime(), info.Size()})
+	if err != nil {
+		return
+	}
+	ioutil.WriteFile(path, r, 0644)
+}
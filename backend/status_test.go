@@ -0,0 +1,120 @@
+package backend
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackend_Status(t *testing.T) {
+	b, cleanup := newTestBackend(t, "")
+	defer cleanup()
+	if _, err := b.Status(); Code(err) != CodeNotFound {
+		t.Errorf("Backend.Status() of an empty timesheet error = %v, want %s", err, CodeNotFound)
+	}
+	b.Add([]string{"hello"})
+	b.Add([]string{"write docs"})
+	status, err := b.Status()
+	if err != nil || status.Task != "write docs" {
+		t.Fatalf("Backend.Status() = %+v, %v, want write docs", status, err)
+	}
+
+	// a fresh sidecar is trusted without reading the timesheet
+	path := statusPath(b.config.omwFile)
+	info, _ := os.Stat(b.config.omwFile)
+	writeStatus(path, &Status{ID: "x", Task: "from sidecar"}, info)
+	if status, _ := b.Status(); status.Task != "from sidecar" {
+		t.Errorf("Backend.Status() = %q, want the sidecar's task", status.Task)
+	}
+
+	// changing the timesheet outside of omw makes the sidecar stale
+	data := "[[entries]]\n  id = \"1\"\n  end = 2020-03-02T09:00:00Z\n  task = \"edited by hand\"\n"
+	if err := ioutil.WriteFile(b.config.omwFile, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := b.Status(); status.Task != "edited by hand" {
+		t.Errorf("Backend.Status() = %q, want the task from the timesheet", status.Task)
+	}
+	r, _ := ioutil.ReadFile(path)
+	saved := statusFile{}
+	json.Unmarshal(r, &saved)
+	if saved.Task != "edited by hand" {
+		t.Errorf("Backend.Status() left the sidecar at %q", saved.Task)
+	}
+}
+
+func TestStatus_Overdue(t *testing.T) {
+	since := time.Date(2020, 3, 2, 9, 0, 0, 0, time.UTC)
+	s := &Status{Task: "write docs", Since: since}
+	tests := []struct {
+		elapsed time.Duration
+		limit   time.Duration
+		want    bool
+	}{
+		{time.Hour, 4 * time.Hour, false},
+		{4 * time.Hour, 4 * time.Hour, false},
+		{6 * time.Hour, 4 * time.Hour, true},
+		{6 * time.Hour, 0, false},
+	}
+	for _, tt := range tests {
+		if got := s.Overdue(since.Add(tt.elapsed), tt.limit); got != tt.want {
+			t.Errorf("Status.Overdue() after %s with limit %s = %v, want %v", tt.elapsed, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestBackend_Current(t *testing.T) {
+	b, cleanup := newTestBackend(t, "")
+	defer cleanup()
+	if entry, err := b.Current(); entry != nil || err != nil {
+		t.Fatalf("Backend.Current() of an empty timesheet = %+v, %v, want nil", entry, err)
+	}
+	tests := []struct {
+		task   string
+		title  string
+		brk    bool
+		ignore bool
+	}{
+		{"fix login @acme", "fix login", false, false},
+		{"lunch **", "lunch", true, false},
+		{"commute ***", "commute", false, true},
+	}
+	for _, tt := range tests {
+		before := time.Now()
+		added, err := b.Add(strings.Fields(tt.task))
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry, err := b.Current()
+		if err != nil || entry == nil {
+			t.Fatalf("Backend.Current() after adding %q = %+v, %v", tt.task, entry, err)
+		}
+		if entry.ID != added[0].ID || entry.Title != tt.title || entry.Brk != tt.brk || entry.Ignore != tt.ignore {
+			t.Errorf("Backend.Current() = %q break %v ignore %v, want %q break %v ignore %v", entry.Title, entry.Brk, entry.Ignore, tt.title, tt.brk, tt.ignore)
+		}
+		if entry.Duration < 0 || entry.Duration > time.Since(before)+time.Second {
+			t.Errorf("Backend.Current() elapsed = %s, want the time since the entry was added", entry.Duration)
+		}
+	}
+}
+
+func TestBackend_Current_blankTask(t *testing.T) {
+	b, cleanup := newTestBackend(t, `
+[[entries]]
+  id = "a"
+  end = 2020-03-02T09:00:00Z
+  task = "fix login"
+
+[[entries]]
+  id = "b"
+  end = 2020-03-02T10:00:00Z
+  task = ""
+`)
+	defer cleanup()
+	if entry, err := b.Current(); entry != nil || err != nil {
+		t.Errorf("Backend.Current() with a blank newest task = %+v, %v, want nil", entry, err)
+	}
+}
@@ -0,0 +1,114 @@
+package backend
+
+import (
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// StandupTemplateString defines the built-in "standup" report style, a
+// short list of the tasks worked on each day
+var StandupTemplateString = `{{$day := "" -}}
+{{range .Entries -}}
+{{if ne $day (.End.Format "2006-01-02") -}}
+{{if $day}}
+{{end -}}
+{{$day = .End.Format "2006-01-02" -}}
+{{style "bold"}}{{.End.Format "Monday, Jan 2"}}{{style "reset"}}
+{{end -}}
+{{if not (or .Brk .Ignore .Unaccounted (eq .Duration 0)) -}}
+- {{.Title}}{{if .Project}} @{{.Project}}{{end}}
+{{end -}}
+{{end -}}
+`
+
+// DetailedTemplateString defines the built-in "detailed" report style,
+// which lists everything known about each entry
+var DetailedTemplateString = `Report Start: {{.From.Format "2006-01-02"}}
+Report End: {{(.To.AddDate 0 0 -1).Format "2006-01-02"}}
+{{- if .Timezone}}
+Times In: {{.Timezone}}
+{{- end}}
+{{- if .Options.Round}}
+Durations Rounded To: {{.Options.Round}}
+{{- end}}
+Total Task Hours: {{.TaskHrs}}
+Total Billable Hours: {{.BillableHrs}}
+Total Break Hours: {{.BrkHrs}}
+Total Ignore Hours: {{.IgnoreHrs}}
+{{range .Entries}}
+{{.Start.Format "2006-01-02 15:04"}}-{{.Ts.Format "15:04"}} {{printf "%10s" .Duration.String}}  {{.Title}}
+{{- if .Brk}} [break]{{end}}
+{{- if .Ignore}} [ignore]{{end}}
+{{- if .Unaccounted}} [unaccounted]{{end}}
+{{- if .Project}} @{{.Project}}{{end}}
+{{- if .Client}} !{{.Client}}{{end}}
+{{- range .Tags}} #{{.}}{{end}}
+{{- if .Estimate}} est:{{.Estimate}}{{end}}
+{{- end}}
+`
+
+// builtinStyles are the report styles available without configuration
+var builtinStyles = map[string]*string{
+	"default":  &TemplateString,
+	"detailed": &DetailedTemplateString,
+	"invoice":  &InvoiceTemplateString,
+	"standup":  &StandupTemplateString,
+}
+
+// reportTemplate returns the text template for a report
+// A style configured under [styles] names a template file and takes
+// precedence over a built-in style of the same name.  Style names match
+// regardless of case.
+func (b *Backend) reportTemplate(report Report) (string, error) {
+	style := strings.ToLower(report.Options.Style)
+	if style == "" {
+		switch {
+		case report.Options.AccuracyCheck:
+			return AccuracyTemplateString, nil
+		case report.Options.RawDurations:
+			return RawDurationsTemplateString, nil
+		case report.Options.Distribution:
+			return DistributionTemplateString, nil
+		case report.Options.Calendar:
+			return CalendarTemplateString, nil
+		case report.Options.AccountFor > 0:
+			return AccountTemplateString, nil
+		case report.Invoice != nil:
+			return InvoiceTemplateString, nil
+		}
+		return TemplateString, nil
+	}
+	if fn, ok := b.config.settings.Styles[style]; ok {
+		tmpl, err := ioutil.ReadFile(fn)
+		if err != nil {
+			return "", errors.Wrapf(err, "can't read template for style %s", style)
+		}
+		return string(tmpl), nil
+	}
+	tmpl, ok := builtinStyles[style]
+	if !ok {
+		return "", errors.Errorf("unknown report style %q - valid styles are %s", style, strings.Join(b.styleNames(), ", "))
+	}
+	if style == "invoice" && report.Invoice == nil {
+		return "", errors.New("the invoice style needs --invoice <client>")
+	}
+	return *tmpl, nil
+}
+
+// styleNames lists the built-in and configured report styles
+func (b *Backend) styleNames() []string {
+	names := []string{}
+	for name := range builtinStyles {
+		names = append(names, name)
+	}
+	for name := range b.config.settings.Styles {
+		if _, ok := builtinStyles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
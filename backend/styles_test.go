@@ -0,0 +1,44 @@
+package backend
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackend_reportTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "omw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fn := filepath.Join(dir, "weekly.tmpl")
+	if err := ioutil.WriteFile(fn, []byte("weekly {{.TaskHrs}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b := &Backend{config: &config{settings: Settings{Styles: map[string]string{"weeklysummary": fn}}}}
+	tests := []struct {
+		name    string
+		style   string
+		want    string
+		wantErr bool
+	}{
+		{"default", "", TemplateString, false},
+		{"built-in", "detailed", DetailedTemplateString, false},
+		{"built-in mixed case", "Detailed", DetailedTemplateString, false},
+		{"configured mixed case", "WeeklySummary", "weekly {{.TaskHrs}}", false},
+		{"unknown", "monthly", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := b.reportTemplate(Report{Options: ReportOptions{Style: tt.style}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Backend.reportTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Backend.reportTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package backend
+
+import (
+	"strings"
+	"time"
+)
+
+// DaySwitches counts the task switches of a day
+type DaySwitches struct {
+	Date     time.Time `json:"date"`
+	Switches int       `json:"switches"`
+}
+
+// taskSwitches counts how often consecutive tasks of the same day have
+// different titles, a rough measure of how fragmented each day was
+// Breaks and ignored time don't end a task, so returning to the same
+// task after lunch is not a switch.  Titles are compared ignoring case
+// and surrounding space.
+func taskSwitches(entries []ReportEntry) (int, []DaySwitches) {
+	total := 0
+	days := []DaySwitches{}
+	last := ""
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Brk || entry.Ignore || entry.Unaccounted || entry.Duration == 0 || isHello(entry) {
+			continue
+		}
+		n := len(days)
+		if n == 0 || !sameDay(days[n-1].Date, entry.Ts) {
+			y, m, d := entry.Ts.Date()
+			days = append(days, DaySwitches{Date: time.Date(y, m, d, 0, 0, 0, 0, entry.Ts.Location())})
+			last = ""
+			n++
+		}
+		title := strings.ToLower(strings.TrimSpace(entry.Title))
+		if last != "" && title != last {
+			days[n-1].Switches++
+			total++
+		}
+		last = title
+	}
+	return total, days
+}
@@ -0,0 +1,30 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_taskSwitches(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2020, 3, day, hour, 0, 0, 0, time.UTC)
+	}
+	entries := []ReportEntry{
+		{Title: "hello", Ts: at(2, 9)},
+		{Title: "write docs", Duration: time.Hour, Ts: at(2, 10)},
+		{Title: "review", Duration: time.Hour, Ts: at(2, 11)},
+		{Title: "lunch", Brk: true, Duration: time.Hour, Ts: at(2, 12)},
+		{Title: "Review ", Duration: time.Hour, Ts: at(2, 13)},
+		{Title: "write docs", Duration: time.Hour, Ts: at(2, 14)},
+		{Title: "hello", Ts: at(3, 9)},
+		{Title: "review", Duration: time.Hour, Ts: at(3, 10)},
+		{Title: "meeting", Duration: time.Hour, Ts: at(3, 11)},
+	}
+	total, days := taskSwitches(entries)
+	if total != 3 {
+		t.Errorf("taskSwitches() total = %d, want 3", total)
+	}
+	if len(days) != 2 || days[0].Switches != 2 || days[1].Switches != 1 {
+		t.Errorf("taskSwitches() days = %+v, want 2 switches on March 2 and 1 on March 3", days)
+	}
+}
@@ -0,0 +1,62 @@
+package backend
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pelletier/go-toml"
+	"github.com/pkg/errors"
+)
+
+// Validate runs the checks of validateEdit() against the timesheet at fn
+// without changing it, for use in hooks such as a git pre-commit hook
+// Instead of fixing duplicate IDs it reports them, along with entries
+// missing an ID, end time or task.  With inOrder it also reports entries
+// that are out of order, oldest first or newest first according to the
+// newest_first setting.
+// The returned error is only set if fn can't be read or parsed at all.
+func (b *Backend) Validate(fn string, inOrder bool) ([]string, error) {
+	r, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, withCode(CodeNotFound, errors.Wrapf(err, "can't read %s", fn))
+	}
+	data := SavedItems{}
+	err = toml.Unmarshal(r, &data)
+	if err != nil {
+		return nil, withCode(CodeCorrupt, errors.Wrapf(err, "%s is not a valid timesheet", fn))
+	}
+
+	problems := []string{}
+	seen := make(map[string]int)
+	for i, e := range data.Entries {
+		n := i + 1
+		switch {
+		case e.ID == "":
+			problems = append(problems, fmt.Sprintf("entry %d: missing id", n))
+		case seen[e.ID] > 0:
+			problems = append(problems, fmt.Sprintf("entry %d: duplicate id %s, first used by entry %d", n, e.ID, seen[e.ID]))
+		default:
+			seen[e.ID] = n
+		}
+		if e.End.IsZero() {
+			problems = append(problems, fmt.Sprintf("entry %d: missing end time", n))
+		}
+		if e.Task == "" {
+			problems = append(problems, fmt.Sprintf("entry %d: missing task", n))
+		}
+		if e.Duration < 0 {
+			problems = append(problems, fmt.Sprintf("entry %d: negative duration %s", n, e.Duration))
+		}
+		if !inOrder || i == 0 || e.End.IsZero() {
+			continue
+		}
+		prev := data.Entries[i-1].End
+		if b.config.settings.NewestFirst && e.End.After(prev) {
+			problems = append(problems, fmt.Sprintf("entry %d: ends after the entry above it", n))
+		}
+		if !b.config.settings.NewestFirst && e.End.Before(prev) {
+			problems = append(problems, fmt.Sprintf("entry %d: ends before the entry above it", n))
+		}
+	}
+	return problems, nil
+}
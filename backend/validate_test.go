@@ -0,0 +1,51 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBackend_Validate(t *testing.T) {
+	data := `
+[[entries]]
+  id = "1"
+  end = 2020-01-02T10:00:00Z
+  task = "review"
+[[entries]]
+  id = "1"
+  end = 2020-01-02T09:00:00Z
+  task = ""
+[[entries]]
+  id = "2"
+  end = 2020-01-02T11:00:00Z
+  task = "standup"
+`
+	b, cleanup := newTestBackend(t, data)
+	defer cleanup()
+	tests := []struct {
+		name    string
+		inOrder bool
+		want    []string
+	}{
+		{"default checks", false, []string{
+			"entry 2: duplicate id 1, first used by entry 1",
+			"entry 2: missing task",
+		}},
+		{"in order", true, []string{
+			"entry 2: duplicate id 1, first used by entry 1",
+			"entry 2: missing task",
+			"entry 2: ends before the entry above it",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := b.Validate(b.config.omwFile, tt.inOrder)
+			if err != nil {
+				t.Fatalf("Backend.Validate() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Backend.Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
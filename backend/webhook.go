@@ -0,0 +1,100 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// webhookAttempts is how many times a webhook is tried before giving up
+	webhookAttempts = 3
+	// webhookTimeout bounds each webhook request
+	webhookTimeout = 5 * time.Second
+	// webhookWait bounds how long Close() waits for webhooks still being
+	// sent, so a dead endpoint doesn't hold up the command line
+	webhookWait = 2 * time.Second
+)
+
+// webhookBackoff is the delay before the first retry, doubled for each
+// retry after that
+var webhookBackoff = 500 * time.Millisecond
+
+// notifyWebhooks POSTs each of entries as JSON to every configured
+// webhook URL in the background
+// The entries are already saved, so failures are only logged.  All URLs
+// are notified concurrently and each is retried with exponential backoff.
+// Close() waits up to webhookWait for them to finish.
+func (b *Backend) notifyWebhooks(entries []SavedEntry) {
+	urls := b.config.settings.Webhooks
+	if len(urls) == 0 {
+		return
+	}
+	client := &http.Client{Timeout: webhookTimeout}
+	for i := range entries {
+		body, err := json.Marshal(entries[i])
+		if err != nil {
+			log.Printf("webhook: can't marshal entry %s: %v", entries[i].ID, err)
+			continue
+		}
+		for _, url := range urls {
+			b.webhooks.Add(1)
+			go func(url string) {
+				defer b.webhooks.Done()
+				err := postWebhook(client, url, body)
+				if err != nil {
+					log.Printf("webhook: %s: %v", url, err)
+				}
+			}(url)
+		}
+	}
+}
+
+// waitWebhooks waits up to d for webhooks still being sent and reports
+// whether they all finished
+func (b *Backend) waitWebhooks(d time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		b.webhooks.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(d):
+		log.Printf("webhook: gave up waiting after %s", d)
+		return false
+	}
+}
+
+// postWebhook delivers body to url, retrying failed requests and server
+// errors
+func postWebhook(client *http.Client, url string, body []byte) error {
+	var err error
+	delay := webhookBackoff
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		var resp *http.Response
+		resp, err = client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 300 {
+			return nil
+		}
+		err = errors.Errorf("unexpected status %s", resp.Status)
+		if resp.StatusCode < 500 {
+			// client errors won't succeed on retry
+			break
+		}
+	}
+	return err
+}
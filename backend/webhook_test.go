@@ -0,0 +1,66 @@
+package backend
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackend_notifyWebhooks(t *testing.T) {
+	defer func(d time.Duration) { webhookBackoff = d }(webhookBackoff)
+	webhookBackoff = time.Millisecond
+	var calls int32
+	var got SavedEntry
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// fail the first attempt to exercise the retry
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	b, cleanup := newTestBackend(t, "")
+	defer cleanup()
+	b.config.settings.Webhooks = []string{srv.URL}
+	entry, err := b.addEntry("deploy @acme")
+	if err != nil {
+		t.Fatalf("Backend.addEntry() error = %v", err)
+	}
+	if !b.waitWebhooks(time.Second) {
+		t.Fatal("webhook still running")
+	}
+	if calls := atomic.LoadInt32(&calls); calls != 2 {
+		t.Errorf("webhook called %d times, want 2", calls)
+	}
+	if got.ID != entry.ID || got.Task != entry.Task {
+		t.Errorf("webhook got %+v, want %+v", got, *entry)
+	}
+}
+
+func TestBackend_notifyWebhooks_unreachable(t *testing.T) {
+	defer func(d time.Duration) { webhookBackoff = d }(webhookBackoff)
+	webhookBackoff = time.Hour
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	b, cleanup := newTestBackend(t, "")
+	defer cleanup()
+	b.config.settings.Webhooks = []string{srv.URL}
+	start := time.Now()
+	if _, err := b.Add([]string{"deploy"}); err != nil {
+		t.Fatalf("Backend.Add() error = %v", err)
+	}
+	if b.waitWebhooks(10 * time.Millisecond) {
+		t.Error("webhook finished despite waiting an hour to retry")
+	}
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("Backend.Add() took %s waiting for a failing webhook", took)
+	}
+}
@@ -0,0 +1,31 @@
+package backend
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ParseWeekday converts a weekday name such as "sunday" or "Mon" into a
+// time.Weekday
+func ParseWeekday(s string) (time.Weekday, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		day := strings.ToLower(d.String())
+		if name == day || (len(name) >= 3 && strings.HasPrefix(day, name)) {
+			return d, nil
+		}
+	}
+	return time.Sunday, errors.Errorf("invalid weekday %q - expected a day name such as monday or sunday", s)
+}
+
+// WeekRange returns the first and last day of the week containing t, for
+// weeks that begin on start
+func WeekRange(t time.Time, start time.Weekday) (time.Time, time.Time) {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) - int(start) + 7) % 7
+	first := day.AddDate(0, 0, -offset)
+	return first, first.AddDate(0, 0, 6)
+}
@@ -0,0 +1,124 @@
+package backend
+
+import (
+	"strings"
+	"unicode"
+)
+
+// wideRanges are the blocks of characters terminals draw two columns
+// wide: East Asian wide and fullwidth forms plus emoji
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115F},   // Hangul Jamo initials
+	{0x231A, 0x231B},   // watch, hourglass
+	{0x23E9, 0x23F3},   // media control symbols
+	{0x25FD, 0x25FE},   // small squares
+	{0x2614, 0x2615},   // umbrella, hot beverage
+	{0x2648, 0x2653},   // zodiac
+	{0x26A1, 0x26A1},   // high voltage
+	{0x26AA, 0x26AB},   // circles
+	{0x26BD, 0x26BE},   // soccer ball, baseball
+	{0x26C4, 0x26C5},   // snowman, sun behind cloud
+	{0x26D4, 0x26D4},   // no entry
+	{0x26EA, 0x26EA},   // church
+	{0x26F2, 0x26F5},   // fountain .. sailboat
+	{0x26FA, 0x26FD},   // tent .. fuel pump
+	{0x2705, 0x2705},   // check mark button
+	{0x270A, 0x270B},   // raised fist, hand
+	{0x2728, 0x2728},   // sparkles
+	{0x274C, 0x274C},   // cross mark
+	{0x2753, 0x2755},   // question and exclamation marks
+	{0x2795, 0x2797},   // plus, minus, divide
+	{0x2B1B, 0x2B1C},   // large squares
+	{0x2B50, 0x2B50},   // star
+	{0x2E80, 0x303E},   // CJK radicals, punctuation
+	{0x3041, 0x33FF},   // kana, CJK compatibility
+	{0x3400, 0x4DBF},   // CJK extension A
+	{0x4E00, 0x9FFF},   // CJK unified ideographs
+	{0xA000, 0xA4CF},   // Yi
+	{0xAC00, 0xD7A3},   // Hangul syllables
+	{0xF900, 0xFAFF},   // CJK compatibility ideographs
+	{0xFE30, 0xFE4F},   // CJK compatibility forms
+	{0xFF00, 0xFF60},   // fullwidth forms
+	{0xFFE0, 0xFFE6},   // fullwidth signs
+	{0x1F300, 0x1F64F}, // pictographs, emoticons
+	{0x1F680, 0x1F6FF}, // transport and map symbols
+	{0x1F900, 0x1F9FF}, // supplemental symbols and pictographs
+	{0x1FA70, 0x1FAFF}, // symbols and pictographs extended-A
+	{0x20000, 0x3FFFD}, // CJK extensions B and later
+}
+
+// runeWidth returns the number of terminal columns r takes up
+// Combining marks, zero-width joiners and variation selectors take up
+// none, as they modify the character before them.
+func runeWidth(r rune) int {
+	if r == 0x200D || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) ||
+		unicode.Is(unicode.Cf, r) || unicode.Is(unicode.Variation_Selector, r) {
+		return 0
+	}
+	for _, w := range wideRanges {
+		if r < w.lo {
+			break
+		}
+		if r <= w.hi {
+			return 2
+		}
+	}
+	return 1
+}
+
+// displayWidth returns the number of terminal columns s takes up, which
+// differs from its length in bytes or runes for emoji and CJK characters
+func displayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+// pad right-aligns s in a column of width characters
+// Unlike printf's %12s it counts display columns rather than bytes, so
+// symbols such as € and wide characters line up.
+func pad(width int, s string) string {
+	if n := displayWidth(s); n < width {
+		return strings.Repeat(" ", width-n) + s
+	}
+	return s
+}
+
+// padRight left-aligns s in a column of width characters
+func padRight(width int, s string) string {
+	if n := displayWidth(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// truncate shortens s to at most width display columns, ending it with
+// an ellipsis if anything was cut, or returns s unchanged if width is 0
+func truncate(width int, s string) string {
+	if width <= 0 || displayWidth(s) <= width {
+		return s
+	}
+	var sb strings.Builder
+	n := 0
+	for _, r := range s {
+		if n+runeWidth(r) > width-1 {
+			break
+		}
+		sb.WriteRune(r)
+		n += runeWidth(r)
+	}
+	return sb.String() + "…"
+}
+
+// truncateTitles returns a copy of entries with their titles shortened
+// to width display columns
+func truncateTitles(entries []ReportEntry, width int) []ReportEntry {
+	short := make([]ReportEntry, len(entries))
+	for i, entry := range entries {
+		entry.Title = truncate(width, entry.Title)
+		short[i] = entry
+	}
+	return short
+}
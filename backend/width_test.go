@@ -0,0 +1,58 @@
+package backend
+
+import "testing"
+
+func Test_displayWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"ascii", "write docs", 10},
+		{"accented", "café", 4},
+		{"combining accent", "café", 4},
+		{"currency", "€12", 3},
+		{"cjk", "会议", 4},
+		{"fullwidth", "ＡＢ", 4},
+		{"emoji", "🚀 launch", 9},
+		{"emoji with variation selector", "☕️ break", 8},
+		{"zwj sequence", "👩‍💻", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := displayWidth(tt.s); got != tt.want {
+				t.Errorf("displayWidth(%q) = %d, want %d", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_padRight(t *testing.T) {
+	// columns after the padded titles should start at the same offset
+	for _, title := range []string{"standup", "会议", "🚀 launch"} {
+		if got := displayWidth(padRight(12, title)); got != 12 {
+			t.Errorf("displayWidth(padRight(12, %q)) = %d, want 12", title, got)
+		}
+	}
+	if got := pad(6, "会议"); got != "  会议" {
+		t.Errorf("pad(6, %q) = %q, want %q", "会议", got, "  会议")
+	}
+}
+
+func Test_truncate(t *testing.T) {
+	tests := []struct {
+		width int
+		s     string
+		want  string
+	}{
+		{0, "write the quarterly report", "write the quarterly report"},
+		{30, "write the quarterly report", "write the quarterly report"},
+		{10, "write the quarterly report", "write the…"},
+		{5, "会议会议会议", "会议…"},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.width, tt.s); got != tt.want {
+			t.Errorf("truncate(%d, %q) = %q, want %q", tt.width, tt.s, got, tt.want)
+		}
+	}
+}
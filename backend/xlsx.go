@@ -0,0 +1,262 @@
+package backend
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// XLSXHeader lists the columns of the spreadsheet written by FormatXLSX
+var XLSXHeader = []string{
+	"Date",
+	"Start",
+	"End",
+	"Duration",
+	"Task",
+	"Project",
+	"Client",
+	"Tags",
+	"Type",
+}
+
+// Cell styles, indexes into cellXfs in xlsxStyles
+// The first four styles have shaded variants xlsxShaded places further
+// on, used for break and ignore rows.
+const (
+	xlsxPlain = iota
+	xlsxDate
+	xlsxClock
+	xlsxDuration
+	xlsxShadedPlain
+	xlsxShadedDate
+	xlsxShadedClock
+	xlsxShadedDuration
+	xlsxBold
+	xlsxBoldDuration
+)
+
+// xlsxShaded is the offset from a style to its shaded variant
+const xlsxShaded = xlsxShadedPlain - xlsxPlain
+
+// xlsxStyles defines the number formats, fonts, fills and cell styles of
+// the spreadsheet.  Durations use [h]:mm:ss so totals can exceed a day.
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="3"><numFmt numFmtId="164" formatCode="yyyy-mm-dd"/><numFmt numFmtId="165" formatCode="hh:mm"/><numFmt numFmtId="166" formatCode="[h]:mm:ss"/></numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="3"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill><fill><patternFill patternType="solid"><fgColor rgb="FFD9D9D9"/><bgColor indexed="64"/></patternFill></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="10">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="166" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="0" fontId="0" fillId="2" borderId="0" xfId="0" applyFill="1"/>
+<xf numFmtId="164" fontId="0" fillId="2" borderId="0" xfId="0" applyNumberFormat="1" applyFill="1"/>
+<xf numFmtId="165" fontId="0" fillId="2" borderId="0" xfId="0" applyNumberFormat="1" applyFill="1"/>
+<xf numFmtId="166" fontId="0" fillId="2" borderId="0" xfId="0" applyNumberFormat="1" applyFill="1"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="166" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/>
+</cellXfs>
+</styleSheet>`
+
+// xlsxParts are the fixed parts of the workbook package
+var xlsxParts = map[string]string{
+	"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`,
+	"_rels/.rels": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`,
+	"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Report" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`,
+	"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`,
+	"xl/styles.xml": xlsxStyles,
+}
+
+// xlsxPartOrder keeps the content types first, as some readers expect
+var xlsxPartOrder = []string{
+	"[Content_Types].xml",
+	"_rels/.rels",
+	"xl/workbook.xml",
+	"xl/_rels/workbook.xml.rels",
+	"xl/styles.xml",
+}
+
+// formatXLSX renders the entries of a report as an Excel workbook with a
+// bold header, one row per entry and SUMIF totals of each entry type
+// Break and ignore rows are shaded.  Zero-length entries such as the
+// first entry of each day are left out.
+func formatXLSX(report Report) (string, error) {
+	sheet := &xlsxSheet{}
+	sheet.row(xlsxBold, XLSXHeader...)
+	// the cached totals add up the same rows as the SUMIFs
+	sums := map[string]time.Duration{}
+	for _, entry := range report.Entries {
+		if entry.Duration == 0 {
+			continue
+		}
+		shade := 0
+		if entry.Brk || entry.Ignore {
+			shade = xlsxShaded
+		}
+		sheet.next()
+		sheet.number(xlsxSerial(entry.Start), xlsxDate+shade)
+		sheet.number(xlsxSerial(entry.Start), xlsxClock+shade)
+		sheet.number(xlsxSerial(entry.Start.Add(entry.Duration)), xlsxClock+shade)
+		sheet.number(entry.Duration.Hours()/24, xlsxDuration+shade)
+		sheet.text(entry.Title, xlsxPlain+shade)
+		sheet.text(entry.Project, xlsxPlain+shade)
+		sheet.text(entry.Client, xlsxPlain+shade)
+		sheet.text(strings.Join(entry.Tags, " "), xlsxPlain+shade)
+		sheet.text(entryType(entry), xlsxPlain+shade)
+		sums[entryType(entry)] += entry.Duration
+	}
+	last := sheet.rows
+	sheet.next()
+	totals := []struct {
+		label string
+		kind  string
+	}{
+		{"Total Task Hours", "task"},
+		{"Total Break Hours", "break"},
+		{"Total Ignore Hours", "ignore"},
+	}
+	for _, t := range totals {
+		sheet.next()
+		sheet.skip(2)
+		sheet.text(t.label, xlsxBold)
+		sheet.formula(fmt.Sprintf(`SUMIF(I2:I%d,"%s",D2:D%d)`, last, t.kind, last), sums[t.kind].Hours()/24, xlsxBoldDuration)
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range xlsxPartOrder {
+		if err := writeZipPart(w, name, xlsxParts[name]); err != nil {
+			return "", err
+		}
+	}
+	if err := writeZipPart(w, "xl/worksheets/sheet1.xml", sheet.xml()); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// entryType names the kind of time an entry tracks
+func entryType(entry ReportEntry) string {
+	switch {
+	case entry.Brk:
+		return "break"
+	case entry.Ignore:
+		return "ignore"
+	case entry.Unaccounted:
+		return "unaccounted"
+	}
+	return "task"
+}
+
+// writeZipPart adds a file to the workbook package
+func writeZipPart(w *zip.Writer, name, content string) error {
+	f, err := w.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write([]byte(content))
+	return err
+}
+
+// xlsxSerial converts the wall clock time of t to an Excel date serial,
+// the number of days since 1899-12-30
+func xlsxSerial(t time.Time) float64 {
+	y, m, d := t.Date()
+	wall := time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	return wall.Sub(time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)).Hours() / 24
+}
+
+// xlsxSheet builds the XML of a worksheet a cell at a time
+type xlsxSheet struct {
+	buf  bytes.Buffer
+	rows int
+	col  int
+}
+
+// row starts a new row holding the given text cells
+func (s *xlsxSheet) row(style int, values ...string) {
+	s.next()
+	for _, v := range values {
+		s.text(v, style)
+	}
+}
+
+// next ends the current row and starts a new one
+func (s *xlsxSheet) next() {
+	if s.rows > 0 {
+		s.buf.WriteString("</row>")
+	}
+	s.rows++
+	s.col = 0
+	fmt.Fprintf(&s.buf, `<row r="%d">`, s.rows)
+}
+
+// skip leaves n empty cells
+func (s *xlsxSheet) skip(n int) {
+	s.col += n
+}
+
+// ref returns the reference of the next cell and moves past it
+func (s *xlsxSheet) ref() string {
+	s.col++
+	return fmt.Sprintf("%c%d", 'A'+s.col-1, s.rows)
+}
+
+func (s *xlsxSheet) text(v string, style int) {
+	fmt.Fprintf(&s.buf, `<c r="%s" s="%d" t="inlineStr"><is><t>`, s.ref(), style)
+	xml.EscapeText(&s.buf, []byte(v))
+	s.buf.WriteString("</t></is></c>")
+}
+
+func (s *xlsxSheet) number(v float64, style int) {
+	fmt.Fprintf(&s.buf, `<c r="%s" s="%d"><v>%g</v></c>`, s.ref(), style, v)
+}
+
+// formula writes a formula cell along with its value, so readers that
+// don't recalculate still show the total
+func (s *xlsxSheet) formula(f string, v float64, style int) {
+	fmt.Fprintf(&s.buf, `<c r="%s" s="%d"><f>`, s.ref(), style)
+	xml.EscapeText(&s.buf, []byte(f))
+	fmt.Fprintf(&s.buf, "</f><v>%g</v></c>", v)
+}
+
+// xml returns the complete worksheet
+func (s *xlsxSheet) xml() string {
+	var out strings.Builder
+	out.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<cols><col min="1" max="3" width="12" customWidth="1"/><col min="4" max="4" width="20" customWidth="1"/><col min="5" max="5" width="40" customWidth="1"/></cols>
+<sheetData>`)
+	out.Write(s.buf.Bytes())
+	if s.rows > 0 {
+		out.WriteString("</row>")
+	}
+	out.WriteString("</sheetData>\n</worksheet>")
+	return out.String()
+}
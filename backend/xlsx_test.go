@@ -0,0 +1,100 @@
+package backend
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_formatXLSX(t *testing.T) {
+	start := time.Date(2020, 3, 2, 9, 0, 0, 0, time.UTC)
+	// the totals come from the rows, not the report's own totals
+	report := Report{
+		TaskHrs: 8 * time.Hour,
+		Entries: []ReportEntry{
+			{Start: start, Ts: start},
+			{Start: start, Ts: start.Add(90 * time.Minute), Duration: 90 * time.Minute, Title: "R&D <draft> > done", Project: "acme"},
+			{Start: start.Add(90 * time.Minute), Ts: start.Add(105 * time.Minute), Duration: 15 * time.Minute, Title: "coffee", Brk: true},
+		},
+	}
+	out, err := formatXLSX(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader([]byte(out)), int64(len(out)))
+	if err != nil {
+		t.Fatalf("formatXLSX() isn't a zip archive: %v", err)
+	}
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name] = string(content)
+	}
+	if zr.File[0].Name != "[Content_Types].xml" {
+		t.Errorf("formatXLSX() first part = %s, want [Content_Types].xml", zr.File[0].Name)
+	}
+	sheet, ok := parts["xl/worksheets/sheet1.xml"]
+	if !ok {
+		t.Fatal("formatXLSX() has no sheet1.xml")
+	}
+	for name, content := range parts {
+		if err := xml.Unmarshal([]byte(content), new(interface{})); err != nil {
+			t.Errorf("formatXLSX() part %s isn't valid XML: %v", name, err)
+		}
+	}
+
+	// Strings are written inline in the sheet rather than to a shared
+	// strings part
+	var ws struct {
+		Rows []struct {
+			Cells []struct {
+				Ref     string `xml:"r,attr"`
+				Style   int    `xml:"s,attr"`
+				Text    string `xml:"is>t"`
+				Value   string `xml:"v"`
+				Formula string `xml:"f"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal([]byte(sheet), &ws); err != nil {
+		t.Fatal(err)
+	}
+	// header, two entries, a blank row and three totals
+	if len(ws.Rows) != 7 {
+		t.Fatalf("formatXLSX() wrote %d rows, want 7", len(ws.Rows))
+	}
+	header := ws.Rows[0].Cells
+	if len(header) != len(XLSXHeader) || header[4].Text != "Task" || header[4].Style != xlsxBold {
+		t.Errorf("formatXLSX() header = %+v", header)
+	}
+	task := ws.Rows[1].Cells
+	if task[4].Ref != "E2" || task[4].Text != "R&D <draft> > done" || task[8].Text != "task" {
+		t.Errorf("formatXLSX() task row = %+v", task)
+	}
+	if !strings.Contains(sheet, "R&amp;D &lt;draft&gt; &gt; done") {
+		t.Error("formatXLSX() didn't escape the title")
+	}
+	brk := ws.Rows[2].Cells
+	if brk[4].Text != "coffee" || brk[4].Style != xlsxPlain+xlsxShaded || brk[8].Text != "break" {
+		t.Errorf("formatXLSX() break row = %+v", brk)
+	}
+	total := ws.Rows[4].Cells
+	if total[0].Ref != "C5" || total[1].Formula != `SUMIF(I2:I3,"task",D2:D3)` || total[1].Value != "0.0625" {
+		t.Errorf("formatXLSX() task total = %+v", total)
+	}
+	if total := ws.Rows[5].Cells; total[1].Formula != `SUMIF(I2:I3,"break",D2:D3)` || !strings.HasPrefix(total[1].Value, "0.0104166") {
+		t.Errorf("formatXLSX() break total = %+v", total)
+	}
+}
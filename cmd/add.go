@@ -27,19 +27,46 @@ var addCmd = &cobra.Command{
 	Short: "Add argument <task> and current time to end of timesheet",
 	Long: `Add <task> should be run at the end of a task before switching focus.
 	Add '**' after your task to categorize it as break time (ie: lunch)
+	Add '**:<category>' instead of '**' to group breaks in reports (ie: '**:meeting')
 	Add '***' after your task to categorize it as time to ignore (ie: commuting)
+	Add '@name' anywhere in your task to assign it to project 'name'
+	Use '/' to nest sub-projects, for example '@acme/backend'
+	Add '#name' anywhere in your task to tag it with 'name'
+	Add '!name' anywhere in your task to record it as work for client 'name'
+	Add '$' or '$0' anywhere in your task to mark it billable or non-billable
+	Separate tasks with '||' to log them at the same time, for example
+	'review || standup'.  Reports split the time since the previous entry
+	evenly between them, rounded down to the second with any remainder
+	going to the first task.  The separator is set by 'split_separator'.
+	Add 'est:<duration>' anywhere in your task to record an estimate, for
+	example 'est:2h' or 'est:1h30m', and compare it with 'report --estimates'
+
+	Billable status is resolved in order of precedence: the '$'/'$0' token on
+	the task, then the project's 'billable' setting in the config file, then
+	the client's 'billable' setting, then the global 'billable' setting.
 	`,
 	Example: `
 	omw add finish meeting with team
 	omw add break **
+	omw add team sync **:meeting
 	omw add commuting ***
+	omw add fix login bug @acme
+	omw add internal sync @acme $0
+	omw add write migration @acme est:3h
+	omw add "code review @acme || standup @internal"
 	`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
 			fmt.Fprintf(os.Stderr, "Missing task after add command!\n")
 			os.Exit(1)
 		}
-		server.Add(args)
+		entries, err := server.Add(args)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			fmt.Printf("Added %q at %s\n", entry.Task, entry.End.Format("15:04"))
+		}
 		return nil
 	},
 }
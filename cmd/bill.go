@@ -0,0 +1,70 @@
+// Copyright © 2019 David McPike
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// BillID is the full or short ID of the entry omw bill changes
+var BillID string
+
+// BillOn marks the entry as billable
+var BillOn bool
+
+// BillOff marks the entry as not billable
+var BillOff bool
+
+// billCmd represents the bill command
+var billCmd = &cobra.Command{
+	Use:   "bill",
+	Short: "Mark an existing entry as billable or not billable",
+	Long: `Bill adds a '$' modifier to the task of an entry to mark it as
+billable, or '$0' to mark it as not billable, replacing any it had.  The
+modifier wins over the billable defaults of its project and client.
+
+Find the ID of an entry with omw report --show-ids.`,
+	Example: `
+	omw bill --id 5c1f9a7e --off
+	omw bill --id 5c1f9a7e --on`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if BillID == "" {
+			return errors.New("missing --id of the entry to change")
+		}
+		if BillOn == BillOff {
+			return errors.New("use exactly one of --on or --off")
+		}
+		entry, err := server.SetBillable(BillID, BillOn)
+		if err != nil {
+			return err
+		}
+		state := "not billable"
+		if BillOn {
+			state = "billable"
+		}
+		fmt.Printf("Marked %q as %s\n", entry.Task, state)
+		return nil
+	},
+}
+
+func init() {
+	billCmd.Flags().StringVar(&BillID, "id", "", "Full or short ID of the entry")
+	billCmd.Flags().BoolVar(&BillOn, "on", false, "Mark the entry as billable")
+	billCmd.Flags().BoolVar(&BillOff, "off", false, "Mark the entry as not billable")
+	rootCmd.AddCommand(billCmd)
+}
@@ -0,0 +1,70 @@
+// Copyright © 2019 David McPike
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// BreakID is the full or short ID of the entry omw break changes
+var BreakID string
+
+// BreakOn marks the entry as a break
+var BreakOn bool
+
+// BreakOff marks the entry as a task again
+var BreakOff bool
+
+// breakCmd represents the break command
+var breakCmd = &cobra.Command{
+	Use:   "break",
+	Short: "Mark an existing entry as a break or not",
+	Long: `Break adds a '**' modifier to the task of an entry to mark it as a
+break, replacing an ignore modifier, or removes it along with any break
+category to count the entry as a task again.
+
+Find the ID of an entry with omw report --show-ids.`,
+	Example: `
+	omw break --id 5c1f9a7e --on
+	omw break --id 5c1f9a7e --off`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if BreakID == "" {
+			return errors.New("missing --id of the entry to change")
+		}
+		if BreakOn == BreakOff {
+			return errors.New("use exactly one of --on or --off")
+		}
+		entry, err := server.SetBreak(BreakID, BreakOn)
+		if err != nil {
+			return err
+		}
+		state := "a task"
+		if BreakOn {
+			state = "a break"
+		}
+		fmt.Printf("Marked %q as %s\n", entry.Task, state)
+		return nil
+	},
+}
+
+func init() {
+	breakCmd.Flags().StringVar(&BreakID, "id", "", "Full or short ID of the entry")
+	breakCmd.Flags().BoolVar(&BreakOn, "on", false, "Mark the entry as a break")
+	breakCmd.Flags().BoolVar(&BreakOff, "off", false, "Mark the entry as a task")
+	rootCmd.AddCommand(breakCmd)
+}
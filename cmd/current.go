@@ -0,0 +1,68 @@
+// Copyright © 2019 David McPike
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// currentCmd represents the current command
+var currentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Show the task you added last and how long ago",
+	Long: `Current prints the title of the most recent entry and the time since
+it was added, and says so if it was a break or ignored time.`,
+	Example: `
+	omw current`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entry, err := server.Current()
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			fmt.Println("No active task")
+			return nil
+		}
+		label := "Working on"
+		if entry.Brk {
+			label = "On a break"
+		} else if entry.Ignore {
+			label = "Ignoring time"
+		}
+		title := entry.Title
+		if title == "" {
+			title = "(untitled)"
+		}
+		fmt.Printf("%s: %s (elapsed %s)\n", label, title, elapsed(entry.Duration))
+		return nil
+	},
+}
+
+// elapsed formats d to the minute, such as 1h23m
+func elapsed(d time.Duration) string {
+	d = d.Truncate(time.Minute)
+	if d < time.Minute {
+		return "0m"
+	}
+	return strings.TrimSuffix(d.String(), "0s")
+}
+
+func init() {
+	rootCmd.AddCommand(currentCmd)
+}
@@ -0,0 +1,54 @@
+// Copyright © 2019 David McPike
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// DeleteForce allows deleting the only entry of the timesheet
+var DeleteForce bool
+
+// deleteCmd represents the delete command
+var deleteCmd = &cobra.Command{
+	Use:   "delete <id>",
+	Short: "Remove an entry from your timesheet",
+	Long: `Delete removes the entry with the given full or short ID, as shown by
+omw report --show-ids, without opening the whole timesheet in an editor.
+The time it tracked goes to the entry after it.
+A backup of your timesheet is saved with a .bak extension first.`,
+	Example: `
+	omw delete 5c1f9a7e
+	omw delete 5c1f9a7e-2b4d-4e6f-8a1c-3d5e7f9b1a2c`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("expected the ID of the entry to delete")
+		}
+		entry, err := server.Delete(args[0], DeleteForce)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Deleted %q ending %s\n", entry.Task, entry.End.Format("2006-01-02 15:04"))
+		return nil
+	},
+}
+
+func init() {
+	deleteCmd.Flags().BoolVar(&DeleteForce, "force", false, "Allow deleting the only entry of the timesheet")
+	rootCmd.AddCommand(deleteCmd)
+}
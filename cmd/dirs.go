@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// DataDirEnv overrides the directory holding the timesheet and its
+	// backups, like the --data-dir flag
+	DataDirEnv = "OMW_DIR"
+	// ConfigDirEnv overrides the directory searched for omw.toml, like the
+	// --config-dir flag
+	ConfigDirEnv = "OMW_CONFIG_DIR"
+)
+
+// dataDir returns the directory omw keeps its timesheet in
+// --data-dir or OMW_DIR wins, then an existing timesheet in the DefaultDir
+// used by earlier versions, then the platform's data directory:
+// $XDG_DATA_HOME/omw (~/.local/share/omw) on Linux and other Unixes,
+// ~/Library/Application Support/omw on macOS and %APPDATA%\omw on Windows.
+func dataDir(home string) string {
+	if dir := viper.GetString("data_dir"); dir != "" {
+		return dir
+	}
+	legacy := filepath.Join(home, DefaultDir)
+	if _, err := os.Stat(filepath.Join(legacy, DefaultFile)); err == nil {
+		return legacy
+	}
+	switch runtime.GOOS {
+	case "windows":
+		if dir := os.Getenv("APPDATA"); dir != "" {
+			return filepath.Join(dir, "omw")
+		}
+	case "darwin":
+		return filepath.Join(home, "Library", "Application Support", "omw")
+	default:
+		if dir := os.Getenv("XDG_DATA_HOME"); dir != "" && filepath.IsAbs(dir) {
+			return filepath.Join(dir, "omw")
+		}
+	}
+	return legacy
+}
+
+// configDir returns the directory for the omw config file
+// --config-dir or OMW_CONFIG_DIR wins, then the platform's directory:
+// $XDG_CONFIG_HOME/omw (~/.config/omw) on Linux and other Unixes,
+// ~/Library/Application Support/omw on macOS and %APPDATA%\omw on Windows.
+func configDir(home string) string {
+	if dir := viper.GetString("config_dir"); dir != "" {
+		return dir
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join(home, ".config", "omw")
+	}
+	return filepath.Join(dir, "omw")
+}
@@ -0,0 +1,133 @@
+// Copyright © 2019 David McPike
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// setenv sets the environment variables in env, unsetting those that are
+// empty, and returns a func that restores their previous values
+func setenv(env map[string]string) func() {
+	old := map[string]*string{}
+	for key, value := range env {
+		if prev, ok := os.LookupEnv(key); ok {
+			old[key] = &prev
+		} else {
+			old[key] = nil
+		}
+		if value == "" {
+			os.Unsetenv(key)
+		} else {
+			os.Setenv(key, value)
+		}
+	}
+	return func() {
+		for key, prev := range old {
+			if prev == nil {
+				os.Unsetenv(key)
+			} else {
+				os.Setenv(key, *prev)
+			}
+		}
+	}
+}
+
+// setFlag sets a persistent flag of rootCmd, if value isn't empty, and
+// returns a func that unsets it again
+func setFlag(t *testing.T, name, value string) func() {
+	flag := rootCmd.PersistentFlags().Lookup(name)
+	if value != "" {
+		if err := flag.Value.Set(value); err != nil {
+			t.Fatal(err)
+		}
+		flag.Changed = true
+	}
+	return func() {
+		flag.Value.Set(flag.DefValue)
+		flag.Changed = false
+	}
+}
+
+func Test_dataDir(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("XDG_DATA_HOME is only used on Linux and other Unixes")
+	}
+	home, err := ioutil.TempDir("", "omw-home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	legacy := filepath.Join(home, DefaultDir)
+	xdg := filepath.Join(home, "xdg")
+	tests := []struct {
+		name   string
+		legacy bool
+		flag   string
+		omwDir string
+		xdg    string
+		want   string
+	}{
+		{"--data-dir wins over everything", true, "/srv/flag", "/srv/omw", xdg, "/srv/flag"},
+		{"OMW_DIR wins over the rest", true, "", "/srv/omw", xdg, "/srv/omw"},
+		{"existing legacy timesheet", true, "", "", xdg, legacy},
+		{"XDG_DATA_HOME", false, "", "", xdg, filepath.Join(xdg, "omw")},
+		{"relative XDG_DATA_HOME is ignored", false, "", "", "xdg", legacy},
+		{"nothing set", false, "", "", "", legacy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.RemoveAll(legacy)
+			if tt.legacy {
+				if err := os.MkdirAll(legacy, 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := ioutil.WriteFile(filepath.Join(legacy, DefaultFile), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			defer setenv(map[string]string{"HOME": home, DataDirEnv: tt.omwDir, "XDG_DATA_HOME": tt.xdg})()
+			defer setFlag(t, "data-dir", tt.flag)()
+			if got := dataDir(home); got != tt.want {
+				t.Errorf("dataDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_configDir(t *testing.T) {
+	tests := []struct {
+		name string
+		flag string
+		env  string
+		want string
+	}{
+		{"--config-dir wins", "/srv/flag", "/srv/env", "/srv/flag"},
+		{"OMW_CONFIG_DIR", "", "/srv/env", "/srv/env"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer setenv(map[string]string{ConfigDirEnv: tt.env})()
+			defer setFlag(t, "config-dir", tt.flag)()
+			if got := configDir("/home/omw"); got != tt.want {
+				t.Errorf("configDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
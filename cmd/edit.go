@@ -15,6 +15,9 @@
 package cmd
 
 import (
+	"fmt"
+
+	"github.com/mcdafydd/omw/backend"
 	"github.com/spf13/cobra"
 )
 
@@ -31,10 +34,25 @@ var editCmd = &cobra.Command{
 				break
 			}
 		}
-		return err
+		if err != nil {
+			return err
+		}
+		if summary := server.LastEdit(); summary != nil {
+			printEditSummary(summary)
+		}
+		return nil
 	},
 }
 
+// printEditSummary shows what the saved edit changed
+func printEditSummary(s *backend.EditSummary) {
+	fmt.Printf("Saved %d entries (%d before the edit), %d added or changed\n", s.After, s.Before, s.Changed)
+	if s.FixedIDs > 0 {
+		fmt.Printf("Replaced %d duplicate IDs\n", s.FixedIDs)
+	}
+	fmt.Printf("Backup of the previous timesheet written to %s\n", s.Backup)
+}
+
 func init() {
 	rootCmd.AddCommand(editCmd)
 }
@@ -32,7 +32,11 @@ var helloCmd = &cobra.Command{
 	start of your first task.
  
         If you do not use hello, omw report will calculate the length of your 
-        first task of the day from midnight of the current day.`,
+        first task of the day from midnight of the current day.
+
+        Set 'hello_starts_day = true' in your config file to make hello, rather
+        than midnight, the start of each day in reports.  Tasks that run past
+        midnight then keep their full duration until your next hello.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) > 0 {
 			fmt.Fprintf(os.Stderr, "Unused arguments provided after hello command\n")
@@ -0,0 +1,64 @@
+// Copyright © 2019 David McPike
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// MergeOut names the file omw merge-file writes to instead of the timesheet
+var MergeOut string
+
+// mergeFileCmd represents the merge-file command
+var mergeFileCmd = &cobra.Command{
+	Use:   "merge-file <file>...",
+	Short: "Combine diverging copies of a timesheet",
+	Long: `Merge-file combines timesheets that have diverged, for example when
+	the same timesheet was updated on two machines without syncing.
+	Entries are combined by ID and sorted by time.  If two entries share
+	an ID but differ, the one omw changed last is kept, or the one from
+	the most recently modified file for entries saved by older versions.
+
+	Without --out the files are merged into your current timesheet, and a
+	backup is saved with a .bak extension first.  With --out only the
+	given files are merged and the result is written to the --out file.`,
+	Example: `
+	omw merge-file ~/Dropbox/omw.toml
+	omw merge-file a.toml b.toml --out merged.toml
+	`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		result, err := server.MergeFiles(args, MergeOut)
+		if err != nil {
+			return err
+		}
+		for _, conflict := range result.Conflicts {
+			fmt.Printf("Duplicate ID %s\n", conflict)
+		}
+		out := MergeOut
+		if out == "" {
+			out = "timesheet"
+		}
+		fmt.Printf("Merged %d entries into %s, reconciled %d duplicate IDs\n", result.Entries, out, len(result.Conflicts))
+		return nil
+	},
+}
+
+func init() {
+	mergeFileCmd.Flags().StringVarP(&MergeOut, "out", "o", "", "Write the merged timesheet to this file instead of your current timesheet")
+	rootCmd.AddCommand(mergeFileCmd)
+}
@@ -0,0 +1,64 @@
+// Copyright © 2019 David McPike
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// MigrateTo names the timezone that omw migrate-tz converts entries to
+var MigrateTo = "utc"
+
+// migrateTZCmd represents the migrate-tz command
+var migrateTZCmd = &cobra.Command{
+	Use:   "migrate-tz",
+	Short: "Convert the timestamps stored in your timesheet to UTC or local time",
+	Long: `Migrate-tz rewrites every timestamp in your timesheet as UTC, or in
+	your local timezone with --to local.  Run it once after changing the
+	'utc' setting in your config file so that existing entries match the
+	new ones.  Each entry still refers to the same moment in time, so your
+	reports will not change.
+	A backup of your timesheet is saved with a .bak extension first.`,
+	Example: `
+	omw migrate-tz --to utc
+	omw migrate-tz --to local
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var loc *time.Location
+		switch MigrateTo {
+		case "utc":
+			loc = time.UTC
+		case "local":
+			loc = time.Local
+		default:
+			return errors.Errorf("unsupported timezone %q - valid values are \"utc\" and \"local\"", MigrateTo)
+		}
+		converted, err := server.MigrateTZ(loc)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Converted %d entries to %s\n", converted, MigrateTo)
+		return nil
+	},
+}
+
+func init() {
+	migrateTZCmd.Flags().StringVar(&MigrateTo, "to", "utc", "Timezone to store timestamps in - valid values are \"utc\" and \"local\"")
+	rootCmd.AddCommand(migrateTZCmd)
+}
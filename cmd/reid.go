@@ -0,0 +1,46 @@
+// Copyright © 2019 David McPike
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// reidCmd represents the reid command
+var reidCmd = &cobra.Command{
+	Use:   "reid",
+	Short: "Give every entry in your timesheet a new ID",
+	Long: `Reid replaces the ID of every entry with a new random one, leaving
+	times and tasks unchanged.  Run it on a copied or imported timesheet
+	before merging it elsewhere so that its IDs can't collide.
+	A backup of your timesheet is saved with a .bak extension first.`,
+	Example: `
+	omw reid
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		changed, err := server.Reid()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Changed %d IDs\n", changed)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reidCmd)
+}
@@ -16,9 +16,14 @@ package cmd
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/mcdafydd/omw/backend"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -31,6 +36,151 @@ var To string
 // Format defines the string output format for the report (text or json)
 var Format = "text"
 
+// RunningBalance adds a cumulative flex time balance to the report
+var RunningBalance bool
+
+// IncludeEmptyDays lists every day of the report period with its hours
+var IncludeEmptyDays bool
+
+// AnnotateOvertime flags the hours of each day past expected_hours
+var AnnotateOvertime bool
+
+// AccuracyCheck lists suspicious entries instead of the usual report
+var AccuracyCheck bool
+
+// RawDurations lists how each entry's duration was calculated
+var RawDurations bool
+
+// Distribution totals task time by hour of day instead of the usual report
+var Distribution bool
+
+// FillGaps adds unaccounted entries for untracked parts of the working day
+var FillGaps bool
+
+// ProjectTree adds project totals rolled up through the project hierarchy
+var ProjectTree bool
+
+// Estimates compares estimated and actual hours of tasks with est: tokens
+var Estimates bool
+
+// Invoice renders the report as an invoice to the given client
+var Invoice string
+
+// Clients limits the report to the given clients
+var Clients []string
+
+// Projects limits the report to the given projects
+var Projects []string
+
+// ExcludeProjects drops the given projects from the report
+var ExcludeProjects []string
+
+// Tags limits the report to entries with any of the given tags
+var Tags []string
+
+// ExcludeTags drops entries with the given tags from the report
+var ExcludeTags []string
+
+// Locale overrides the language of the day headers in text reports
+var Locale string
+
+// GroupBy nests task hours by these keys, such as project,day
+var GroupBy []string
+
+// Calendar shows the task hours of each day as a month calendar
+var Calendar bool
+
+// AccountFor checks that each day adds up to this many hours
+var AccountFor time.Duration
+
+// AccountTolerance is how far a day may be from AccountFor
+var AccountTolerance time.Duration
+
+// Sort orders the report entries, such as -duration
+var Sort string
+
+// Limit caps the number of entries shown
+var Limit int
+
+// Tail shows the last --limit entries instead of the first
+var Tail bool
+
+// TZConvert shows the report times in this IANA timezone
+var TZConvert string
+
+// Round rounds each entry's duration to a multiple of this
+var Round time.Duration
+
+// BreakPolicy pays breaks shorter than paid_break_limit
+var BreakPolicy bool
+
+// ExcludeWeekendBreaks leaves off-hours breaks out of the break totals
+var ExcludeWeekendBreaks bool
+
+// Email wraps the report in a subject, greeting and sign-off
+var Email bool
+
+// MinDuration drops entries shorter than this from the report
+var MinDuration time.Duration
+
+// ExcludeIDs drops the entries with the given IDs from the report
+var ExcludeIDs []string
+
+// PerDayFile writes one report file per day into the --output directory
+var PerDayFile bool
+
+// Currency overrides the currency symbol of invoice amounts
+var Currency string
+
+// Output names a file to write the report to instead of stdout
+var Output string
+
+// Style names the template used for text output
+var Style string
+
+// ShowIDs adds short entry IDs to the text report
+var ShowIDs bool
+
+// TitleMaxLength truncates long task titles in text output
+var TitleMaxLength int
+
+// File is a timesheet to report on instead of the configured one, or -
+// for stdin
+var File string
+
+// ShowSource adds the timesheet each entry came from to the output
+var ShowSource bool
+
+// AggregateBreaks shows one line for the breaks of each day
+var AggregateBreaks bool
+
+// AggregateIgnored shows one line for the ignored entries of each day
+var AggregateIgnored bool
+
+// Plain prints one terse line per entry instead of the text template
+var Plain bool
+
+// EntriesOnly lists the entries without computing report totals
+var EntriesOnly bool
+
+// NoHook skips the report_hook from config
+var NoHook bool
+
+// ModifiedSince limits the report to entries added or changed after this time
+var ModifiedSince string
+
+// Week reports on the current week
+var Week bool
+
+// FiscalYear reports on a fiscal year, 0 for the current one
+var FiscalYear int
+
+// FiscalQuarter reports on a quarter of the fiscal year
+var FiscalQuarter string
+
+// StartOfWeek overrides the start_of_week setting
+var StartOfWeek string
+
 var defaultTs string
 
 // reportCmd represents the report command
@@ -44,27 +194,411 @@ var reportCmd = &cobra.Command{
 	--from YYYY-MM-DD --to YYYY-MM-DD 
 
 	to provide start and optional end dates for the report.
-        If end date is not specified, end date will be today.`,
+        If end date is not specified, end date will be today.
+	Dates may also be written as 01/02/2006, 2.1.2006, Jan 2 2006 or
+	2 Jan 2006.  Set date_order = "dmy" in your config file to read
+	01/02/2006 as the 1st of February.`,
 	Example: `
 	omw report
 	omw report --from 2019-01-01 
 	omw report --from 2019-01-01 --to 2019-01-04
+	omw report --from "Jan 1 2019" --to 01/04/2019
+	omw report --from 2019-01-01 --running-balance
+	omw report --week --start-of-week sunday
+	omw report --fiscal-year 2024
+	omw report --fiscal-quarter Q1
+	omw report --project acme --exclude-tag internal
+	omw report --week --tag acme --tag billing
+	omw report --project-tree
+	omw report --show-ids
+	omw report --week --estimates
+	omw report --from 2019-01-01 --fill-gaps
+	omw report --from 2019-01-01 --accuracy-check
+	omw report --from 2019-01-01 --min-duration 5m
+	omw report --week --group-by project,day
+	omw report --week --sort -duration
+	omw report --week --exclude-weekend-breaks
+	omw report --week --break-policy
+	omw report --from 2019-01-01 --to 2019-01-31 --include-empty-days
+	omw report --week --annotate-overtime
+	omw report --week --plain
+	omw report --week --aggregate-breaks
+	omw report --from 2019-01-01 --limit 20 --tail
+	cat archive.toml | omw report --file - --from 2019-01-01 --to 2019-01-31
+	omw report --week --tz-convert America/New_York
+	omw report --from 2019-01-01 --distribution
+	omw report --from 2019-01-01 --to 2019-01-31 --calendar
+	omw report --week --account-for 8h --tolerance 5m
+	omw report --raw-durations
+	omw report --from 2019-01-01 --to 2019-01-31 --invoice acme
+	omw report --from 2019-01-01 --to 2019-01-31 --invoice acme --currency €
+	omw report --from 2019-01-01 --to 2019-01-31 --invoice acme --exclude-ids 1b4e28ba,6fa459ea
+	omw report --week --style standup
+	omw report --week --locale de
+	omw report --week --style standup --email
+	omw report --from 2019-01-01 --format csv > timesheet.csv
+	omw report --week --round 15m
+	omw report --week --format markdown
+	omw report --week --format ics --output week.ics
+	omw report --from 2019-01-01 --format clockify > clockify.csv
+	omw report --from 2019-01-01 --format kimai > kimai.csv
+	omw report --from 2019-01-01 --format xlsx --output report.xlsx
+	omw report --week --format org >> ~/org/clocked.org
+	omw report --format summary --project acme
+	omw report --format prometheus-pushgateway
+	omw report --from 2019-01-01 --to 2019-01-31 --per-day-file --output archive/
+	omw report --from 2019-01-01 --format json --entries-only
+	omw report --from 2019-01-01 --format json --only-modified-since 2020-02-01T09:00:00Z
 	`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		output, err := server.Report(From, To, Format)
+		if Format == "json" {
+			// Execute() reports the error as JSON instead
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+		}
+		if Format == "summary" {
+			// keep the output parseable, Execute() still prints the error
+			cmd.SilenceUsage = true
+		}
+		fiscal := cmd.Flags().Changed("fiscal-year") || cmd.Flags().Changed("fiscal-quarter")
+		if Week && fiscal {
+			return errors.New("--week can't be combined with --fiscal-year or --fiscal-quarter")
+		}
+		if cmd.Flags().Changed("start-of-week") && !Week {
+			return errors.New("--start-of-week requires --week")
+		}
+		if Week {
+			if cmd.Flags().Changed("from") || cmd.Flags().Changed("to") {
+				return errors.New("--week can't be combined with --from or --to")
+			}
+			err := weekRange()
+			if err != nil {
+				return err
+			}
+		}
+		if fiscal {
+			if cmd.Flags().Changed("from") || cmd.Flags().Changed("to") {
+				return errors.New("--fiscal-year and --fiscal-quarter can't be combined with --from or --to")
+			}
+			err := fiscalRange()
+			if err != nil {
+				return err
+			}
+		}
+		if Format == "xlsx" && Output == "" {
+			return errors.New("--format xlsx needs --output <file>")
+		}
+		if Email && Format != "text" {
+			return errors.New("--email only works with --format text")
+		}
+		if Tail && Limit <= 0 {
+			return errors.New("--tail needs --limit <n>")
+		}
+		if Plain && (Format != "text" || cmd.Flags().Changed("style")) {
+			return errors.New("--plain only works with --format text and no --style")
+		}
+		if PerDayFile && Output == "" {
+			return errors.New("--per-day-file needs --output <dir>")
+		}
+		if Output != "" {
+			// color is decided by the terminal, not by where the
+			// report is written
+			settings := server.Settings()
+			settings.Color = false
+			server.Configure(settings)
+		}
+		if Currency != "" {
+			settings := server.Settings()
+			settings.Currency.Symbol = Currency
+			server.Configure(settings)
+		}
+		if cmd.Flags().Changed("title-max-length") {
+			settings := server.Settings()
+			settings.TitleMaxLength = TitleMaxLength
+			server.Configure(settings)
+		}
+		if Locale != "" {
+			locale, err := backend.ParseLocale(Locale)
+			if err != nil {
+				return err
+			}
+			settings := server.Settings()
+			settings.Locale = locale
+			server.Configure(settings)
+		}
+		var zone *time.Location
+		if TZConvert != "" {
+			loc, err := time.LoadLocation(TZConvert)
+			if err != nil {
+				return errors.Errorf("invalid --tz-convert %q - use an IANA timezone such as Europe/Berlin", TZConvert)
+			}
+			zone = loc
+		}
+		since, err := parseModifiedSince(ModifiedSince)
 		if err != nil {
 			return err
 		}
-		fmt.Printf("\n%+v\n", output)
+		opts := backend.ReportOptions{
+			RunningBalance:       RunningBalance,
+			IncludeEmptyDays:     IncludeEmptyDays,
+			AnnotateOvertime:     AnnotateOvertime,
+			AccuracyCheck:        AccuracyCheck,
+			RawDurations:         RawDurations,
+			Distribution:         Distribution,
+			Calendar:             Calendar,
+			AccountFor:           AccountFor,
+			AccountTolerance:     AccountTolerance,
+			FillGaps:             FillGaps,
+			ProjectTree:          ProjectTree,
+			GroupBy:              GroupBy,
+			Estimates:            Estimates,
+			Invoice:              Invoice,
+			Clients:              Clients,
+			Projects:             Projects,
+			ExcludeProjects:      ExcludeProjects,
+			Tags:                 Tags,
+			ExcludeTags:          ExcludeTags,
+			ExcludeIDs:           ExcludeIDs,
+			MinDuration:          MinDuration,
+			ExcludeWeekendBreaks: ExcludeWeekendBreaks,
+			BreakPolicy:          BreakPolicy,
+			Round:                Round,
+			DisplayZone:          zone,
+			Email:                Email,
+			Sort:                 Sort,
+			Limit:                Limit,
+			Tail:                 Tail,
+			Style:                Style,
+			ShowIDs:              ShowIDs,
+			Plain:                Plain,
+			AggregateBreaks:      AggregateBreaks,
+			AggregateIgnored:     AggregateIgnored,
+			ShowSource:           ShowSource,
+			EntriesOnly:          EntriesOnly,
+			NoHook:               NoHook,
+			ModifiedSince:        since,
+		}
+		if File == "-" {
+			opts.Input = os.Stdin
+			opts.InputName = "stdin"
+		} else if File != "" {
+			fp, err := os.Open(File)
+			if err != nil {
+				return errors.Wrapf(err, "can't open --file %s", File)
+			}
+			defer fp.Close()
+			opts.Input = fp
+			opts.InputName = File
+		}
+		if PerDayFile {
+			return writeDayFiles(opts)
+		}
+		output, err := server.Report(From, To, Format, opts)
+		if err != nil {
+			return err
+		}
+		if filtered := server.LastReport().FilteredPercent(); filtered > server.Settings().FilterWarning {
+			fmt.Fprintf(os.Stderr, "Warning: %d%% of entries excluded by filters\n", filtered)
+		}
+		if AccountFor > 0 {
+			return printAccounting(cmd, output)
+		}
+		if Output != "" {
+			return ioutil.WriteFile(Output, []byte(output), 0644)
+		}
+		if Format == "prometheus-pushgateway" && server.Settings().Pushgateway != "" {
+			return server.PushMetrics(output)
+		}
+		printReport(output)
 		return nil
 	},
 }
 
+// printReport writes a report to stdout. Only the formats meant for reading
+// get a blank line before them - one before a CSV header or BEGIN:VCALENDAR
+// breaks some importers
+func printReport(output string) {
+	switch Format {
+	case "text", "json", "fc":
+		fmt.Printf("\n%+v\n", output)
+		return
+	}
+	fmt.Print(output)
+	if output != "" && !strings.HasSuffix(output, "\n") {
+		fmt.Println()
+	}
+}
+
+// weekRange sets From and To to the first and last day of the current week
+func weekRange() error {
+	start := server.Settings().WeekStart
+	if StartOfWeek != "" {
+		day, err := backend.ParseWeekday(StartOfWeek)
+		if err != nil {
+			return err
+		}
+		start = day
+	}
+	first, last := backend.WeekRange(time.Now(), start)
+	From = first.Format("2006-01-02")
+	To = last.Format("2006-01-02")
+	return nil
+}
+
+// parseModifiedSince accepts an RFC 3339 timestamp or a local YYYY-MM-DD date
+func parseModifiedSince(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	since, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		since, err = time.ParseInLocation("2006-01-02", s, time.Local)
+	}
+	if err != nil {
+		return time.Time{}, errors.Errorf("invalid --only-modified-since %q - use YYYY-MM-DD or an RFC 3339 timestamp", s)
+	}
+	return since, nil
+}
+
+// printAccounting shows the result of --account-for and fails if any day
+// doesn't add up, so scripts can check before submitting a timesheet
+func printAccounting(cmd *cobra.Command, output string) error {
+	if Output != "" {
+		err := ioutil.WriteFile(Output, []byte(output), 0644)
+		if err != nil {
+			return err
+		}
+	} else {
+		printReport(output)
+	}
+	acc := server.LastReport().Accounting
+	if acc.Failed > 0 {
+		cmd.SilenceUsage = true
+		return errors.Errorf("%d of %d days don't add up to %s", acc.Failed, len(acc.Days), acc.Target)
+	}
+	return nil
+}
+
+// formatExtensions maps report formats to the extension of their files
+var formatExtensions = map[string]string{
+	"text":                   "txt",
+	"json":                   "json",
+	"fc":                     "json",
+	"clockify":               "csv",
+	"kimai":                  "csv",
+	"csv":                    "csv",
+	"markdown":               "md",
+	"ics":                    "ics",
+	"xlsx":                   "xlsx",
+	"org":                    "org",
+	"summary":                "txt",
+	"prometheus-pushgateway": "prom",
+}
+
+// writeDayFiles saves the report of each day as YYYY-MM-DD.<ext> in the
+// --output directory
+func writeDayFiles(opts backend.ReportOptions) error {
+	reports, err := server.ReportPerDay(From, To, Format, opts)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(Output, 0755)
+	if err != nil {
+		return errors.Wrapf(err, "can't create %s", Output)
+	}
+	ext, ok := formatExtensions[Format]
+	if !ok {
+		ext = "txt"
+	}
+	for _, day := range reports {
+		fn := filepath.Join(Output, fmt.Sprintf("%s.%s", day.Date.Format("2006-01-02"), ext))
+		err = ioutil.WriteFile(fn, []byte(day.Output), 0644)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Wrote %d daily reports to %s\n", len(reports), Output)
+	return nil
+}
+
+// fiscalRange sets From and To to the first and last day of the requested
+// fiscal year or quarter
+func fiscalRange() error {
+	start := server.Settings().FiscalYearStart
+	if start == 0 {
+		start = time.January
+	}
+	year := FiscalYear
+	if year == 0 {
+		year = backend.FiscalYear(time.Now(), start)
+	}
+	quarter := 0
+	if FiscalQuarter != "" {
+		q, err := backend.ParseQuarter(FiscalQuarter)
+		if err != nil {
+			return err
+		}
+		quarter = q
+	}
+	first, last := backend.FiscalRange(year, quarter, start, time.Local)
+	From = first.Format("2006-01-02")
+	To = last.Format("2006-01-02")
+	return nil
+}
+
 func init() {
 	now := time.Now()
 	defaultTs = strings.Fields(now.String())[0] // Should be YYYY-MM-DD
 	reportCmd.Flags().StringVarP(&From, "from", "f", defaultTs, "Beginning date for report output - beginning today if not specified")
 	reportCmd.Flags().StringVarP(&To, "to", "t", defaultTs, "End date for report output - end of today if not specified")
-	reportCmd.Flags().StringVarP(&Format, "format", "a", "text", "Format for report output - valid values are \"text\" or \"json\"")
+	reportCmd.Flags().StringVarP(&Format, "format", "a", "text", "Format for report output - valid values are \"text\", \"json\", \"fc\", \"csv\", \"markdown\", \"ics\", \"clockify\", \"kimai\", \"xlsx\", \"org\", \"summary\" or \"prometheus-pushgateway\"")
+	reportCmd.Flags().StringVarP(&Output, "output", "o", "", "Write the report to this file instead of stdout")
+	reportCmd.Flags().BoolVar(&PerDayFile, "per-day-file", false, "Write one file per day, named by date, into the --output directory")
+	reportCmd.Flags().BoolVarP(&Week, "week", "w", false, "Report on the current week instead of --from and --to")
+	reportCmd.Flags().IntVar(&FiscalYear, "fiscal-year", 0, "Report on this fiscal year, starting in fiscal_year_start (default the current fiscal year)")
+	reportCmd.Flags().StringVar(&FiscalQuarter, "fiscal-quarter", "", "Report on this quarter (Q1-Q4) of --fiscal-year")
+	reportCmd.Flags().StringVar(&StartOfWeek, "start-of-week", "", "First day of the week for --week, overriding start_of_week (default monday); requires --week")
+	reportCmd.Flags().BoolVar(&RunningBalance, "running-balance", false, "Show a per-day running balance of worked minus expected hours")
+	reportCmd.Flags().BoolVar(&AnnotateOvertime, "annotate-overtime", false, "Show each day's hours past expected_hours as overtime")
+	reportCmd.Flags().BoolVar(&IncludeEmptyDays, "include-empty-days", false, "List the hours of every day in the range, including days with nothing tracked")
+	reportCmd.Flags().BoolVar(&AccuracyCheck, "accuracy-check", false, "List entries shorter than min_duration or longer than max_duration")
+	reportCmd.Flags().BoolVar(&RawDurations, "raw-durations", false, "Debug durations by showing the exact timestamps and nanoseconds of each calculation")
+	reportCmd.Flags().BoolVar(&Distribution, "distribution", false, "Show a histogram of task hours by hour of day")
+	reportCmd.Flags().BoolVar(&FillGaps, "fill-gaps", false, "Add unaccounted entries for untracked time between day_start and day_end")
+	reportCmd.Flags().BoolVar(&ProjectTree, "project-tree", false, "Show task hours rolled up through the project/sub-project hierarchy")
+	reportCmd.Flags().BoolVar(&Estimates, "estimates", false, "Compare estimated and actual hours for tasks with an est: token")
+	reportCmd.Flags().StringVar(&Invoice, "invoice", "", "Render an invoice of the billable tasks for this client")
+	reportCmd.Flags().StringVar(&Currency, "currency", "", "Currency symbol for invoice amounts, overriding currency.symbol (default $)")
+	reportCmd.Flags().StringSliceVar(&Clients, "client", nil, "Only include tasks for these clients")
+	reportCmd.Flags().StringSliceVar(&Projects, "project", nil, "Only include tasks in these projects and their sub-projects")
+	reportCmd.Flags().StringSliceVar(&Tags, "tag", nil, "Only include tasks with any of these tags")
+	reportCmd.Flags().StringSliceVar(&ExcludeProjects, "exclude-project", nil, "Drop tasks in these projects, applied after --project")
+	reportCmd.Flags().StringSliceVar(&ExcludeTags, "exclude-tag", nil, "Drop tasks with these tags, applied after --project")
+	reportCmd.Flags().StringVar(&Locale, "locale", "", "Language of the weekday and month names in day headers, such as de or fr (overrides config)")
+	reportCmd.Flags().StringSliceVar(&GroupBy, "group-by", nil, "Add task hours grouped by each of project, client, day, week or tag in turn, such as project,day")
+	reportCmd.Flags().BoolVar(&Calendar, "calendar", false, "Show the task hours of each day as a calendar, highlighting days over or under expected_hours")
+	reportCmd.Flags().DurationVar(&AccountFor, "account-for", 0, "Check that task and break hours add up to this on each day, such as 8h, and fail if not")
+	reportCmd.Flags().DurationVar(&AccountTolerance, "tolerance", backend.DefaultAccountTolerance, "How far a day may be from --account-for and still pass")
+	reportCmd.Flags().StringVar(&Sort, "sort", "", "Order entries by time, or within each day by duration, title or project, with a - prefix for descending")
+	reportCmd.Flags().IntVar(&Limit, "limit", 0, "Show only the first n entries, while the totals still cover all of them")
+	reportCmd.Flags().BoolVar(&Tail, "tail", false, "With --limit, show the last entries instead of the first")
+	reportCmd.Flags().StringVar(&TZConvert, "tz-convert", "", "Show entry times in this IANA timezone, such as America/New_York")
+	reportCmd.Flags().DurationVar(&Round, "round", 0, "Round each entry's duration to the nearest multiple of this, such as 15m or 6m, before totalling")
+	reportCmd.Flags().BoolVar(&BreakPolicy, "break-policy", false, "Count breaks shorter than paid_break_limit from config as paid task hours")
+	reportCmd.Flags().BoolVar(&ExcludeWeekendBreaks, "exclude-weekend-breaks", false, "Leave breaks on weekends or outside day_start to day_end out of the break totals")
+	reportCmd.Flags().BoolVar(&Email, "email", false, "Wrap the report with a subject line, greeting and the sign-off from [email] in config")
+	reportCmd.Flags().DurationVar(&MinDuration, "min-duration", 0, "Drop entries shorter than this, such as 5m, from the entries and totals")
+	reportCmd.Flags().StringSliceVar(&ExcludeIDs, "exclude-ids", nil, "Drop the entries with these IDs, full or as shown by --show-ids")
+	reportCmd.Flags().StringVar(&Style, "style", "", "Text report style - \"default\", \"detailed\", \"standup\", \"invoice\" or a style from [styles] in your config file")
+	reportCmd.Flags().BoolVar(&ShowIDs, "show-ids", false, "Show the short ID of each entry")
+	reportCmd.Flags().StringVar(&File, "file", "", "Report on this timesheet instead of your own, or - to read it from stdin")
+	reportCmd.Flags().BoolVar(&ShowSource, "show-source", false, "Show the timesheet file each entry was read from")
+	reportCmd.Flags().IntVar(&TitleMaxLength, "title-max-length", 0, "Truncate task titles in text and Markdown output to this many characters, 0 for no limit")
+	reportCmd.Flags().BoolVar(&AggregateBreaks, "aggregate-breaks", false, "Show the breaks of each day as one line with their count and total")
+	reportCmd.Flags().BoolVar(&AggregateIgnored, "aggregate-ignored", false, "Show the ignored entries of each day as one line with their count and total")
+	reportCmd.Flags().BoolVar(&Plain, "plain", false, "Print one line per entry and a line of totals, without day banners")
+	reportCmd.Flags().BoolVar(&EntriesOnly, "entries-only", false, "List entries with their durations but skip the report totals")
+	reportCmd.Flags().BoolVar(&NoHook, "no-hook", false, "Don't pipe the report through report_hook from config")
+	reportCmd.Flags().StringVar(&ModifiedSince, "only-modified-since", "", "Only include entries added or changed after this date or RFC 3339 time")
 	rootCmd.AddCommand(reportCmd)
 }
@@ -15,8 +15,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/inconshreveable/mousetrap"
 	"github.com/mcdafydd/omw/backend"
@@ -28,9 +33,13 @@ import (
 
 var cfgFile string
 
+// NoColor disables ANSI styling in all output
+var NoColor bool
+
 const (
-	// DefaultDir is the default directory inside the user's home directory
-	// that will store omw data files
+	// DefaultDir is the directory inside the user's home directory that
+	// stores omw data files where there is no platform data directory,
+	// and where earlier versions always stored them
 	DefaultDir = ".local/share/omw"
 	// DefaultFile is the default filename for the primary time tracking data log
 	DefaultFile = "omw.toml"
@@ -77,29 +86,59 @@ var rootCmd = &cobra.Command{
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// With --format json, errors are written to stderr as a JSON object with
+// a machine-readable code instead.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		if Format == "json" {
+			writeJSONError(os.Stderr, err)
+		} else {
+			fmt.Fprintln(os.Stderr, err)
+		}
 		os.Exit(1)
 	}
 }
 
+// writeJSONError writes err to w as {"error": "...", "code": "..."}
+func writeJSONError(w io.Writer, err error) {
+	json.NewEncoder(w).Encode(struct {
+		Error string            `json:"error"`
+		Code  backend.ErrorCode `json:"code"`
+	}{err.Error(), backend.Code(err)})
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
+	rootCmd.PersistentFlags().BoolVar(&NoColor, "no-color", false, "Disable colored output (also disabled by setting NO_COLOR)")
+
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.omw.toml, or omw.toml in your config directory)")
+	rootCmd.PersistentFlags().String("data-dir", "", "directory holding the timesheet and its backups (default is your data directory, or $"+DataDirEnv+")")
+	rootCmd.PersistentFlags().String("config-dir", "", "directory searched for omw.toml (default is your config directory, or $"+ConfigDirEnv+")")
+	viper.BindPFlag("data_dir", rootCmd.PersistentFlags().Lookup("data-dir"))
+	viper.BindPFlag("config_dir", rootCmd.PersistentFlags().Lookup("config-dir"))
+	viper.BindEnv("data_dir", DataDirEnv)
+	viper.BindEnv("config_dir", ConfigDirEnv)
+}
+
+// openTimesheet creates the data directory and timesheet if they don't
+// exist yet and points server at them
+// It runs after the flags and config file are read, since either can
+// move the data directory.
+func openTimesheet() {
 	home, err := homedir.Dir()
 	if err != nil {
 		errors.Wrap(err, "homedir.Dir() returned error")
 	}
 
 	fm := os.FileMode(0700)
-	omwDir := fmt.Sprintf("%s/%s", home, DefaultDir)
+	omwDir := dataDir(home)
 	err = os.MkdirAll(omwDir, fm)
 	if err != nil {
 		errors.Wrapf(err, "MkdirAll %s", omwDir)
 	}
 
-	omwFile := fmt.Sprintf("%s/%s", omwDir, DefaultFile)
+	omwFile := filepath.Join(omwDir, DefaultFile)
 	if _, err := os.Stat(omwFile); os.IsNotExist(err) {
 		fmt.Println("file does not exist - creating file", omwFile)
 		fp, err := os.OpenFile(omwFile, os.O_APPEND|os.O_RDWR|os.O_CREATE, 0644)
@@ -110,11 +149,6 @@ func init() {
 	}
 
 	server = backend.Create(nil, omwDir, omwFile)
-
-	// Here you will define your flags and configuration settings.
-	// Cobra supports persistent flags, which, if defined here,
-	// will be global for your application.
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.omw.yaml)")
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -122,6 +156,10 @@ func initConfig() {
 	if cfgFile != "" {
 		// Use config file from the flag.
 		viper.SetConfigFile(cfgFile)
+	} else if dir := viper.GetString("config_dir"); dir != "" {
+		// Use omw.toml in the directory from --config-dir or OMW_CONFIG_DIR.
+		viper.AddConfigPath(dir)
+		viper.SetConfigName("omw")
 	} else {
 		// Find home directory.
 		home, err := homedir.Dir()
@@ -138,7 +176,194 @@ func initConfig() {
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+	err := viper.ReadInConfig()
+	if _, ok := err.(viper.ConfigFileNotFoundError); ok && cfgFile == "" {
+		// then look for omw.toml in the platform's config directory
+		home, _ := homedir.Dir()
+		viper.AddConfigPath(configDir(home))
+		viper.SetConfigName("omw")
+		err = viper.ReadInConfig()
+	}
+	if err == nil {
+		// stderr keeps stdout clean for machine-readable report formats
+		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+	}
+
+	openTimesheet()
+	server.Configure(loadSettings())
+}
+
+// loadSettings maps the config file and environment onto backend settings
+func loadSettings() backend.Settings {
+	viper.SetDefault("expected_hours", "8h")
+	viper.SetDefault("min_duration", "1m")
+	viper.SetDefault("max_duration", "4h")
+	viper.SetDefault("filter_warning", 50)
+	viper.SetDefault("paid_break_limit", "15m")
+	viper.SetDefault("split_separator", backend.DefaultSplitSeparator)
+
+	settings := backend.Settings{
+		ExpectedHours:    viper.GetDuration("expected_hours"),
+		FilterWarning:    viper.GetInt("filter_warning"),
+		MinDuration:      viper.GetDuration("min_duration"),
+		MaxDuration:      viper.GetDuration("max_duration"),
+		WeekStart:        weekdaySetting("start_of_week", "monday"),
+		FiscalYearStart:  monthSetting("fiscal_year_start", "january"),
+		DateOrder:        dateOrderSetting("date_order", backend.DateOrderMDY),
+		PaidBreakLimit:   viper.GetDuration("paid_break_limit"),
+		DayStart:         clockSetting("day_start", "09:00"),
+		DayEnd:           clockSetting("day_end", "17:00"),
+		StoreUTC:         viper.GetBool("utc"),
+		NewestFirst:      viper.GetBool("newest_first"),
+		HelloStartsDay:   viper.GetBool("hello_starts_day"),
+		Color:            colorEnabled(),
+		Webhooks:         viper.GetStringSlice("webhooks"),
+		PlainLog:         viper.GetBool("plaintext_log"),
+		Locale:           localeSetting(),
+		TitleMaxLength:   viper.GetInt("title_max_length"),
+		Pushgateway:      viper.GetString("pushgateway_url"),
+		ReportHook:       viper.GetString("report_hook"),
+		SplitSeparator:   viper.GetString("split_separator"),
+		TaskPrefix:       viper.GetString("task_prefix"),
+		TaskSuffix:       viper.GetString("task_suffix"),
+		Billable:         viper.GetBool("billable"),
+		Currency:         currencySetting(),
+		Styles:           make(map[string]string),
+		BillableProjects: make(map[string]bool),
+		Clients:          make(map[string]backend.Client),
+		Consultant: backend.Party{
+			Name:    viper.GetString("invoice.name"),
+			Address: viper.GetString("invoice.address"),
+			Rate:    viper.GetFloat64("invoice.rate"),
+		},
+		Email: backend.Email{
+			To:       viper.GetString("email.to"),
+			Name:     viper.GetString("email.name"),
+			Greeting: viper.GetString("email.greeting"),
+		},
+	}
+	// [styles] maps report style names to template files
+	for name, fn := range viper.GetStringMapString("styles") {
+		path, err := homedir.Expand(fn)
+		if err != nil {
+			path = fn
+		}
+		settings.Styles[strings.ToLower(name)] = path
+	}
+	// [projects.<name>] tables hold per-project overrides, keyed by the
+	// lowercase name since viper lowercases keys
+	for name := range viper.GetStringMap("projects") {
+		key := fmt.Sprintf("projects.%s.billable", name)
+		if viper.IsSet(key) {
+			settings.BillableProjects[strings.ToLower(name)] = viper.GetBool(key)
+		}
+	}
+	// [clients.<name>] tables hold the invoice details of each client,
+	// keyed by the lowercase name like the projects
+	for name := range viper.GetStringMap("clients") {
+		key := fmt.Sprintf("clients.%s", name)
+		client := backend.Client{
+			Party: backend.Party{
+				Name:    viper.GetString(key + ".name"),
+				Address: viper.GetString(key + ".address"),
+				Rate:    viper.GetFloat64(key + ".rate"),
+			},
+		}
+		if viper.IsSet(key + ".billable") {
+			billable := viper.GetBool(key + ".billable")
+			client.Billable = &billable
+		}
+		settings.Clients[strings.ToLower(name)] = client
+	}
+	return settings
+}
+
+// clockSetting parses a HH:MM time of day setting into the offset
+// from midnight, falling back to fallback for invalid values
+func clockSetting(key, fallback string) time.Duration {
+	viper.SetDefault(key, fallback)
+	clock, err := time.Parse("15:04", viper.GetString(key))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid %s %q in config - expected HH:MM, using %s\n", key, viper.GetString(key), fallback)
+		clock, _ = time.Parse("15:04", fallback)
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute
+}
+
+// currencySetting reads how money amounts are written
+func currencySetting() backend.Currency {
+	viper.SetDefault("currency.symbol", backend.DefaultCurrency.Symbol)
+	viper.SetDefault("currency.position", "prefix")
+	viper.SetDefault("currency.decimal_separator", backend.DefaultCurrency.DecimalSeparator)
+	currency := backend.Currency{
+		Symbol:           viper.GetString("currency.symbol"),
+		Suffix:           viper.GetString("currency.position") == "suffix",
+		DecimalSeparator: viper.GetString("currency.decimal_separator"),
+	}
+	if position := viper.GetString("currency.position"); position != "prefix" && position != "suffix" {
+		fmt.Fprintf(os.Stderr, "Invalid currency.position %q in config - expected \"prefix\" or \"suffix\", using prefix\n", position)
+	}
+	if sep := currency.DecimalSeparator; sep != "." && sep != "," {
+		fmt.Fprintf(os.Stderr, "Invalid currency.decimal_separator %q in config - expected \".\" or \",\", using .\n", sep)
+		currency.DecimalSeparator = "."
+	}
+	return currency
+}
+
+// dateOrderSetting reads whether numeric dates are month or day first
+func dateOrderSetting(key, fallback string) string {
+	viper.SetDefault(key, fallback)
+	order := strings.ToLower(viper.GetString(key))
+	if order != backend.DateOrderMDY && order != backend.DateOrderDMY {
+		fmt.Fprintf(os.Stderr, "Invalid %s %q in config - expected %q or %q, using %s\n", key, order, backend.DateOrderMDY, backend.DateOrderDMY, fallback)
+		return fallback
+	}
+	return order
+}
+
+// monthSetting reads a month name or number
+func monthSetting(key, fallback string) time.Month {
+	viper.SetDefault(key, fallback)
+	month, err := backend.ParseMonth(viper.GetString(key))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid %s in config: %v, using %s\n", key, err, fallback)
+		month, _ = backend.ParseMonth(fallback)
+	}
+	return month
+}
+
+// localeSetting reads the language of weekday and month names
+func localeSetting() string {
+	viper.SetDefault("locale", backend.DefaultLocale)
+	locale, err := backend.ParseLocale(viper.GetString("locale"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid locale in config: %v, using %s\n", err, locale)
+	}
+	return locale
+}
+
+// weekdaySetting parses a weekday name setting, falling back to fallback
+// for invalid values
+func weekdaySetting(key, fallback string) time.Weekday {
+	viper.SetDefault(key, fallback)
+	day, err := backend.ParseWeekday(viper.GetString(key))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid %s in config: %v, using %s\n", key, err, fallback)
+		day, _ = backend.ParseWeekday(fallback)
+	}
+	return day
+}
+
+// colorEnabled decides once at startup whether output may be styled
+// Color is only used on a terminal, and never when --no-color is given or
+// the NO_COLOR environment variable is set (https://no-color.org)
+func colorEnabled() bool {
+	if NoColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
 	}
+	return info.Mode()&os.ModeCharDevice != 0
 }
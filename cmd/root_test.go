@@ -14,7 +14,17 @@
 
 package cmd
 
-import "testing"
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
 
 func TestExecute(t *testing.T) {
 	tests := []struct {
@@ -29,6 +39,27 @@ func TestExecute(t *testing.T) {
 	}
 }
 
+func Test_writeJSONError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"plain", errors.New("boom"), `{"error":"boom","code":"error"}`},
+		{"missing file", errors.Wrap(&os.PathError{Op: "open", Path: "omw.toml", Err: os.ErrNotExist}, "can't read"),
+			`{"error":"can't read: open omw.toml: file does not exist","code":"not_found"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeJSONError(&buf, tt.err)
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("writeJSONError() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_initConfig(t *testing.T) {
 	tests := []struct {
 		name string
@@ -41,3 +72,66 @@ func Test_initConfig(t *testing.T) {
 		})
 	}
 }
+
+// withConfig loads config as the config file for the duration of a test
+func withConfig(t *testing.T, config string) func() {
+	dir, err := ioutil.TempDir("", "omw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn := filepath.Join(dir, "omw.toml")
+	if err := ioutil.WriteFile(fn, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	viper.Reset()
+	viper.SetConfigFile(fn)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		viper.Reset()
+		os.RemoveAll(dir)
+	}
+}
+
+func Test_loadSettings_projects(t *testing.T) {
+	defer withConfig(t, `
+[projects.AcmeCorp]
+billable = false
+[projects.Internal]
+billable = true
+`)()
+	settings := loadSettings()
+	want := map[string]bool{"acmecorp": false, "internal": true}
+	if len(settings.BillableProjects) != len(want) {
+		t.Fatalf("loadSettings() BillableProjects = %v, want %v", settings.BillableProjects, want)
+	}
+	for name, billable := range want {
+		if got, ok := settings.BillableProjects[name]; !ok || got != billable {
+			t.Errorf("loadSettings() BillableProjects[%q] = %v, %v, want %v", name, got, ok, billable)
+		}
+	}
+}
+
+func Test_loadSettings_clients(t *testing.T) {
+	defer withConfig(t, `
+[clients.AcmeCorp]
+name = "Acme Corp"
+rate = 150.0
+billable = false
+`)()
+	client, ok := loadSettings().Clients["acmecorp"]
+	if !ok || client.Name != "Acme Corp" || client.Rate != 150 || client.Billable == nil || *client.Billable {
+		t.Errorf("loadSettings() Clients[\"acmecorp\"] = %+v, %v", client, ok)
+	}
+}
+
+func Test_loadSettings_styles(t *testing.T) {
+	defer withConfig(t, `
+[styles]
+WeeklySummary = "/tmp/weekly.tmpl"
+`)()
+	if fn := loadSettings().Styles["weeklysummary"]; fn != "/tmp/weekly.tmpl" {
+		t.Errorf("loadSettings() Styles[\"weeklysummary\"] = %q, want /tmp/weekly.tmpl", fn)
+	}
+}
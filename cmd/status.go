@@ -0,0 +1,55 @@
+// Copyright © 2019 David McPike
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the most recent entry and how long ago it was added",
+	Long: `Status prints the most recent entry of your timesheet and the time
+since it was added, for example to show in a status bar.
+
+It reads a small sidecar file kept up to date by omw instead of the whole
+timesheet, so it is cheap to run every second.
+
+If the task has been running for longer than max_duration from your config
+file, status warns that you may have forgotten to switch.`,
+	Example: `
+	omw status`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		status, err := server.Status()
+		if err != nil {
+			return err
+		}
+		elapsed := time.Since(status.Since).Truncate(time.Minute)
+		if status.Overdue(time.Now(), server.Settings().MaxDuration) {
+			fmt.Printf("%s (⚠ running %s — did you forget to switch?)\n", status.Task, elapsed)
+			return nil
+		}
+		fmt.Printf("%s (%s)\n", status.Task, elapsed)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
@@ -17,6 +17,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -32,7 +33,12 @@ var stretchCmd = &cobra.Command{
 			fmt.Fprintf(os.Stderr, "Unused arguments provided after stretch command\n")
 			os.Exit(1)
 		}
-		return server.Stretch()
+		entry, running, err := server.Stretch()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Stretched %q to %s (running %s)\n", entry.Task, entry.End.Format("15:04"), running.Truncate(time.Second))
+		return nil
 	},
 }
 
@@ -0,0 +1,60 @@
+// Copyright © 2019 David McPike
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// InOrder makes omw validate also check that entries are in time order
+var InOrder bool
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate <file>",
+	Short: "Check a timesheet file for problems without changing it",
+	Long: `Validate runs the same checks as omw edit on any timesheet file: it
+	must be valid TOML and every entry needs a unique ID, an end time and a
+	task.  With --in-order entries must also be sorted by time, newest
+	first if newest_first is set.  Problems are listed and the command
+	exits non-zero, so it can be used in a git pre-commit hook.`,
+	Example: `
+	omw validate ~/.local/share/omw/omw.toml
+	omw validate --in-order timesheet.toml
+	`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		problems, err := server.Validate(args[0], InOrder)
+		if err != nil {
+			return err
+		}
+		for _, problem := range problems {
+			fmt.Printf("%s: %s\n", args[0], problem)
+		}
+		if len(problems) > 0 {
+			return errors.Errorf("found %d problems in %s", len(problems), args[0])
+		}
+		return nil
+	},
+}
+
+func init() {
+	validateCmd.Flags().BoolVar(&InOrder, "in-order", false, "Also check that entries are sorted by time")
+	rootCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,89 @@
+// Copyright © 2019 David McPike
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/spf13/cobra"
+)
+
+// BuildInfo describes the omw binary, as set at build time with
+// -ldflags "-X main.version=... -X main.commit=... -X main.date=..."
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+}
+
+// buildInfo is reported by omw version and --version
+var buildInfo = BuildInfo{
+	Version:   "dev",
+	Commit:    "none",
+	Date:      "unknown",
+	GoVersion: runtime.Version(),
+	Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+}
+
+// VersionJSON prints the build information as JSON
+var VersionJSON bool
+
+// SetBuildInfo records the version, commit and build date of the binary
+// Empty values keep their defaults.  A binary installed with go install
+// has no ldflags, so its module version is used instead.
+func SetBuildInfo(version, commit, date string) {
+	if version != "" {
+		buildInfo.Version = version
+	}
+	if commit != "" {
+		buildInfo.Commit = commit
+	}
+	if date != "" {
+		buildInfo.Date = date
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && buildInfo.Version == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		buildInfo.Version = info.Main.Version
+	}
+	rootCmd.Version = buildInfo.Version
+}
+
+// versionCmd represents the version command
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show the version, commit and build date of omw",
+	Long: `Version prints which build of omw you are running, to include when
+filing an issue.  Use --json for scripts that check compatibility.`,
+	Example: `
+	omw version
+	omw version --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if VersionJSON {
+			return json.NewEncoder(os.Stdout).Encode(buildInfo)
+		}
+		fmt.Printf("omw %s\ncommit: %s\nbuilt: %s\ngo: %s %s\n", buildInfo.Version, buildInfo.Commit, buildInfo.Date, buildInfo.GoVersion, buildInfo.Platform)
+		return nil
+	},
+}
+
+func init() {
+	versionCmd.Flags().BoolVar(&VersionJSON, "json", false, "Print the build information as JSON")
+	rootCmd.AddCommand(versionCmd)
+}
@@ -0,0 +1,95 @@
+// Copyright © 2019 David McPike
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/mcdafydd/omw/backend"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Interval defines how often omw watch redraws the report
+var Interval time.Duration
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Show a live-updating view of today's report",
+	Long: `Watch redraws today's report in place every few seconds, showing
+	your most recent entry, the time elapsed since it, and your running
+	totals for the day.  Press Ctrl-C to exit.`,
+	Example: `
+	omw watch
+	omw watch --interval 30s
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if Interval <= 0 {
+			return errors.Errorf("interval must be positive, got %s", Interval)
+		}
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		defer signal.Stop(interrupt)
+		ticker := time.NewTicker(Interval)
+		defer ticker.Stop()
+
+		for {
+			if err := drawWatch(); err != nil {
+				return err
+			}
+			select {
+			case <-interrupt:
+				fmt.Println()
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+// drawWatch clears the terminal and renders today's report followed by
+// the time elapsed since the most recent entry
+func drawWatch() error {
+	now := time.Now()
+	today := now.Format("2006-01-02")
+	fmt.Print(clearScreen)
+	fmt.Printf("omw watch - %s (every %s, Ctrl-C to exit)\n", now.Format("15:04:05"), Interval)
+	output, err := server.Report(today, today, "text", backend.ReportOptions{})
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	report := server.LastReport()
+	if report == nil || len(report.Entries) == 0 {
+		fmt.Println("\nNo entries yet today")
+		return nil
+	}
+	last := report.Entries[len(report.Entries)-1]
+	elapsed := now.Sub(last.Ts).Truncate(time.Second)
+	fmt.Printf("\n\nLast entry: %s at %s - elapsed %s\n", last.Title, last.Ts.Format("15:04"), elapsed)
+	return nil
+}
+
+func init() {
+	watchCmd.Flags().DurationVarP(&Interval, "interval", "i", 5*time.Second, "How often to redraw the report")
+	rootCmd.AddCommand(watchCmd)
+}
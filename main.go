@@ -4,6 +4,15 @@ import (
 	"github.com/mcdafydd/omw/cmd"
 )
 
+// version, commit and date are set at build time with -ldflags -X, as
+// goreleaser does by default
+var (
+	version string
+	commit  string
+	date    string
+)
+
 func main() {
+	cmd.SetBuildInfo(version, commit, date)
 	cmd.Execute()
 }